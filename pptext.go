@@ -53,6 +53,7 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math"
 	"os"
 	"os/exec"
 	"path"
@@ -77,12 +78,35 @@ var sw []string      // suspect words list
 var rs []string      // array of strings for local aggregation
 var pptr []string    // pptext report
 var puncStyle string // punctuation style American or British
+var dashStyle string // dash convention ASCII or Unicode
+
+// a scanno, the word it is probably a misreading of and an optional
+// pattern the line must match for -f scannos to correct it
+type scanno struct {
+	word string         // the scanno
+	fix  string         // expected correction. may be empty
+	ctx  *regexp.Regexp // context pattern. may be nil
+	re   *regexp.Regexp // the word, between non-letters
+}
+
+// scanno list. with case variants. curly apostrophes
+var scannoList []scanno // scanno list
 
-// scanno list. all lower case. curly apostrophes
-var scannoWordlist []string // scanno word list
+// scannos -f scannos corrects: those with a correction and a context
+// that are not in the good word list
+var scannoFixList []scanno
+
+// good word list entry. scope limits the entry to one check:
+// "spell", "scanno" or "edit". an empty scope applies to all checks
+type goodWord struct {
+	word  string         // the word, or the pattern of a regex entry
+	re    *regexp.Regexp // matches the entry as a whole word
+	scope string         // check the entry applies to
+	regex bool           // entry is a regular expression
+}
 
-// good word list. may have straight or curly apostrophes, mixed case
-var goodWordlist []string // good word list specified by user
+// good word list. curly apostrophes, mixed case
+var goodWordlist []goodWord // good word list specified by user
 
 // mixed case ok words in text
 var okwords []string // ok words in text
@@ -102,13 +126,53 @@ var wordListMapLines map[string]string
 // paragraph buffer
 var pbuf []string
 
+// line of the working buffer each paragraph starts on, 0-based
+var pbufStart []int
+
 // working buffer
 var wbuf []string
 
-// heMap and beMap maps map word sequences to relative frequency of occurence
+// a pair of words that are easily confused, as he/be. forms maps word
+// sequences containing either word to relative frequency of occurence.
 // higher values mean more frequently seen
-var heMap map[string]int
-var beMap map[string]int
+type confusionPair struct {
+	w1, w2 string
+	forms  map[string]int
+	ties   string // word whose forms are also reported at a ratio equal to the paranoia level
+}
+
+// confusion pairs for jeebies. he/be is always first
+var confusionPairs []confusionPair
+
+// one way of writing something in a consistency group. a literal
+// matches as a whole word, ignoring case
+type variant struct {
+	name    string
+	re      *regexp.Regexp
+	literal bool
+}
+
+// ways of writing the same thing, one of which the book should use
+// throughout. a suffix group has only suffixes and is compared stem by stem
+type consistencyGroup struct {
+	variants []variant
+	suffixes []string
+}
+
+// consistency groups for the book level checks
+var consistencyGroups []consistencyGroup
+
+// a check from the project's checks.txt
+type customCheck struct {
+	name    string
+	re      *regexp.Regexp
+	para    bool           // match against paragraphs instead of lines
+	except  *regexp.Regexp // matches inside a match of this are not reported
+	message string
+}
+
+// project checks
+var customChecks []customCheck
 
 // debug messages
 var dbuf []string
@@ -135,6 +199,31 @@ func isRomOrNum(s string) bool {
 	return (t1 == "" || t2 == "" || t3 == "")
 }
 
+// value of a well-formed Roman numeral. ok is false if the numeral is
+// malformed ("IIII", "VX", "IC") or mixes upper and lower case
+var reRoman = regexp.MustCompile(`^M{0,4}(CM|CD|D?C{0,3})(XC|XL|L?X{0,3})(IX|IV|V?I{0,3})$`)
+
+func romanValue(s string) (int, bool) {
+	if s == "" || (s != strings.ToUpper(s) && s != strings.ToLower(s)) {
+		return 0, false
+	}
+	u := strings.ToUpper(s)
+	if !reRoman.MatchString(u) {
+		return 0, false
+	}
+	vals := map[rune]int{'I': 1, 'V': 5, 'X': 10, 'L': 50, 'C': 100, 'D': 500, 'M': 1000}
+	rs := []rune(u)
+	n := 0
+	for i, r := range rs {
+		if i+1 < len(rs) && vals[r] < vals[rs[i+1]] {
+			n -= vals[r]
+		} else {
+			n += vals[r]
+		}
+	}
+	return n, true
+}
+
 // return true if slice contains string
 //
 func contains(s []string, e string) bool {
@@ -239,6 +328,18 @@ type params struct {
 	Revision      bool
 	Debug         bool
 	SelectedTests string // a=all, b-z0-9=selected tests
+	Curl          bool   // convert straight quotes to curly quotes
+	Fixes         string // fix classes to apply, comma separated
+	Candidates    bool   // write suspect words as good word candidates
+	MergeFile     string // approved words to merge into the good words file
+	CorpusDir     string // directory of books to build pairlist.txt from
+	MinCount      int    // least count of a form kept in pairlist.txt
+	Paranoia      int    // jeebies paranoia level, 1 to 3
+	WordSort      string // word frequency order: count, alpha or reverse
+	Profile       string // allowed characters: a profile name or file
+	Normalize     bool   // normalize the text to NFC before the checks
+	Dashes        string // dash convention: auto, unicode or ascii
+	Articles      string // a/an usage: modern or period
 }
 
 var p params
@@ -661,6 +762,23 @@ func asqual(m map[string]int) map[string]int {
 	return m
 }
 
+// quote marks used for outer and inner quotations
+type quoteStyle struct {
+	outerOpen  string
+	outerClose string
+	innerOpen  string
+	innerClose string
+}
+
+var americanQuotes = quoteStyle{"“", "”", "‘", "’"}
+var britishQuotes = quoteStyle{"‘", "’", "“", "”"}
+
+// common words that take a trailing apostrophe (wher’, o’, an’, t’)
+const trailingApostropheWords = `wher|ther|o|an|ha|t`
+
+// common words that take a leading apostrophe (’way, ’twas, ’em, ’ll)
+const leadingApostropheWords = `way|twas|twill|twould|twere|uns|fore|most|em|ud|cos|cept|ll|less|\d+`
+
 // puts output in scanreport.txt in same folder as results.html
 
 func puncScan() []string {
@@ -668,9 +786,10 @@ func puncScan() []string {
 	rs := []string{}  // returned and displayed in pptext report
 	prs := []string{} // saved to scanreport.txt
 
+	// British-style punctuation uses single quotes as the outer quotes
+	qs := americanQuotes
 	if puncStyle == "British" {
-		rs = append(rs, "Smart Quote Checks skipped (British-style punctuation)")
-		return rs
+		qs = britishQuotes
 	}
 
 	rs = append(rs, "☳<a name='sqs'></a>")
@@ -684,7 +803,14 @@ func puncScan() []string {
 	// build the header
 
 	prs = append(prs, BOM+"SMART QUOTE CHECKS (overlay format)")
+	prs = append(prs, fmt.Sprintf("%s-style punctuation: outer quotes %s%s, inner quotes %s%s",
+		puncStyle, qs.outerOpen, qs.outerClose, qs.innerOpen, qs.innerClose))
 	prs = append(prs, "suspect punctuation marked with '@' character")
+	prs = append(prs, "  CODQ/COSQ consecutive open double/single quote")
+	prs = append(prs, "  UCDQ/UCSQ unmatched close double/single quote")
+	prs = append(prs, "  NESK      quote still open at paragraph end")
+	prs = append(prs, "  MOQC      continued quotation: paragraph does not open with a quote")
+	prs = append(prs, "  NCMQ      multi-paragraph quotation never closed")
 	prs = append(prs, "-------------------------------------------------------------------")
 	prs = append(prs, "")
 
@@ -701,9 +827,9 @@ func puncScan() []string {
 	//
 	re81 := regexp.MustCompile(`(\p{L})’(\p{L})`) // mid-word contraction
 	// traililng apostrophe common word list
-	re72 := regexp.MustCompile(`(?i)(^|\P{L})(wher|ther|o|an|ha|t)’($|\P{L})`)
+	re72 := regexp.MustCompile(`(?i)(^|\P{L})(` + trailingApostropheWords + `)’($|\P{L})`)
 	// leading apostrophe common word list
-	re73 := regexp.MustCompile(`(?i)(^|\P{L})’(way|twas|twill|twould|twere|uns|fore|most|em|ud|cos|cept|ll|less|\d+)($|\P{L})`)
+	re73 := regexp.MustCompile(`(?i)(^|\P{L})’(` + leadingApostropheWords + `)($|\P{L})`)
 
 	for i, _ := range lwbuf {
 		lwbuf[i] = re81.ReplaceAllString(lwbuf[i], "$1▿$2")
@@ -715,7 +841,7 @@ func puncScan() []string {
 	awords := make(map[string]int) // apostrophe words
 
 	// accept contractions in good word list
-	for _, word := range goodWordlist {
+	for _, word := range goodWordsFor("") {
 		if strings.Contains(word, "’") {
 			rword := strings.Replace(word, "’", "▿", -1)
 			re51 := regexp.MustCompile(`(?i)(?P<1W>^|\P{L})` + word + `(?P<2W>$|\P{L})`)
@@ -758,18 +884,20 @@ func puncScan() []string {
 	// find all phrases that occur at least twice
 	// mark ends as quote pairs.
 	re29 := regexp.MustCompile(`(?P<1W>‘)(?P<2W>[^’]+)(?P<3W>’)`)
-	i := 0
-	for ; i < len(lwbuf)-1; i++ {
-		// temp join two lines
-		ccl1 := len(lwbuf[i])
-		s := lwbuf[i] + " " + lwbuf[i+1]
-		s = re29.ReplaceAllString(s, "⸢${2W}⸣")
-		// now split and put it back
-		lwbuf[i] = s[:ccl1]
-		lwbuf[i+1] = s[ccl1+1:]
+	cloakPairs := func() {
+		for i := 0; i < len(lwbuf)-1; i++ {
+			// temp join two lines
+			ccl1 := len(lwbuf[i])
+			s := lwbuf[i] + " " + lwbuf[i+1]
+			s = re29.ReplaceAllString(s, "⸢${2W}⸣")
+			// now split and put it back
+			lwbuf[i] = s[:ccl1]
+			lwbuf[i+1] = s[ccl1+1:]
+		}
+	}
+	if puncStyle != "British" {
+		cloakPairs()
 	}
-
-	// - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
 
 	// thinkin’ ?= thinking processing
 	// if a word ends in in’ change it to -ing and see if that's a valid word
@@ -848,6 +976,14 @@ func puncScan() []string {
 
 	// - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
 
+	// British style: the single quotes are the outer quotes, so cloak
+	// the pairs only after the apostrophes so "goin’" does not close one
+	if puncStyle == "British" {
+		cloakPairs()
+	}
+
+	// - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - - -
+
 	// lwbuf is fully munged. scan for possible errors
 
 	re80 := regexp.MustCompile(`[“”‘’]`)
@@ -855,6 +991,28 @@ func puncScan() []string {
 	dqreport := false
 	anyreport := false
 
+	// a quotation running over several paragraphs leaves its outer quote
+	// open at the end of each paragraph and each following paragraph
+	// opens again with an outer quote. only the last one closes.
+	continuing := false // in a multi-paragraph quotation
+	missingOpenAt := -1 // line starting a continuation paragraph with no open quote
+
+	// does the paragraph starting at line n close the outer quote
+	// before opening one
+	closesFirst := func(n int) bool {
+		for ; n < len(lwbuf) && strings.TrimSpace(lwbuf[n]) != ""; n++ {
+			for _, r := range lwbuf[n] {
+				switch string(r) {
+				case qs.outerOpen:
+					return false
+				case qs.outerClose:
+					return true
+				}
+			}
+		}
+		return false
+	}
+
 	for i := 0; i < len(lwbuf); i++ {
 
 		parabreak := false
@@ -863,12 +1021,41 @@ func puncScan() []string {
 			parabreak = true
 		}
 
+		if parabreak && (i == 0 || strings.TrimSpace(lwbuf[i-1]) == "") {
+			continue // not the first blank line after a paragraph
+		}
+
 		if parabreak {
 			// paragraph break.
 			// we are sitting on a blank line between paragraphs or
 			// at the EOF one past the last line.
 			// process what we have and reset
 
+			// first line of the next paragraph, if any
+			next := i + 1
+			for next < len(wbuf) && strings.TrimSpace(wbuf[next]) == "" {
+				next++
+			}
+			onlyOuter := len(pstack) == 1 && pstack[0].punc == qs.outerOpen
+
+			if onlyOuter && next < len(wbuf) &&
+				strings.HasPrefix(strings.TrimLeft(wbuf[next], " _"), qs.outerOpen) {
+				// the quotation continues in the next paragraph
+				continuing = true
+				pstack = []xpuncEvent{}
+				sqreport = false
+				dqreport = false
+				continue
+			}
+			if onlyOuter && next < len(wbuf) && closesFirst(next) {
+				// the quotation continues but the next paragraph does not
+				// open with a quote. keep the stack so the close matches.
+				anyreport = true
+				missingOpenAt = next
+				continuing = false
+				continue
+			}
+
 			// anything on stack at this point is an error
 			if len(pstack) > 0 {
 				stacklst := ""
@@ -877,69 +1064,74 @@ func puncScan() []string {
 				}
 				stacklst = strings.TrimSpace(stacklst)
 				anyreport = true
-				dwbuf[i-1] += fmt.Sprintf("[@NESK %s]", stacklst) // non-empty stack at paragraph end
+				if onlyOuter && continuing {
+					// multi-paragraph quotation never closed
+					dwbuf[i-1] += fmt.Sprintf("[@NCMQ %s]", stacklst)
+				} else {
+					dwbuf[i-1] += fmt.Sprintf("[@NESK %s]", stacklst) // non-empty stack at paragraph end
+				}
 			}
 
+			continuing = false
 			pstack = []xpuncEvent{}
 			sqreport = false
 			dqreport = false
 			continue
 		}
 
+		// insert a report tag into the annotated copy, allowing for the
+		// tags already inserted on this line
+		shift := 0
+		mark := func(at int, tag string) {
+			dwbuf[i] = dwbuf[i][:at+shift] + tag + dwbuf[i][at+shift:]
+			shift += len(tag)
+		}
+		if i == missingOpenAt {
+			mark(0, "[@MOQC]") // missing open quote on continuation paragraph
+		}
+
 		m := re80.FindAllStringSubmatchIndex(lwbuf[i], -1)
 		for _, t := range m {
 			rune, _ := utf8.DecodeRuneInString(lwbuf[i][t[0]:])
 			r := string(rune)
 
-			// if we hit an ODQ, check if it follows another ODQ
+			// an open quote, check if it follows the same open quote
 			// always push
-			if r == "“" {
+			if r == qs.outerOpen || r == qs.innerOpen {
+				reported, tag := &dqreport, "[@CODQ]"
+				if r == "‘" {
+					reported, tag = &sqreport, "[@COSQ]"
+				}
 				r2 := Peek()
-				if !dqreport && r2.punc == "“" {
-					// consecutive ODQ
+				if !*reported && r2.punc == r {
+					// consecutive open quotes
 					anyreport = true
-					dqreport = true
-					dwbuf[i] = dwbuf[i][:t[0]] + "[@CODQ]" + dwbuf[i][t[0]:]
+					*reported = true
+					mark(t[0], tag)
 				}
 				Push(xpuncEvent{r, i, t[0]})
 			}
 
-			// close double quote should be paired with an open double quote on the stack
-			// if so, remove the ODQ on the stack
+			// a close quote should be paired with its open quote on the stack
+			// if so, remove the open quote on the stack
 			// pop only if expected, else leave stack intact
-			if r == "”" {
-				r2 := Peek()
-				if r2.punc == "“" { // expected
-					_ = Pop()
-				} else {
-					anyreport = true
-					dwbuf[i] = dwbuf[i][:t[0]] + "[@UCDQ]" + dwbuf[i][t[0]:]
+			if r == qs.outerClose || r == qs.innerClose {
+				opener, tag := qs.outerOpen, "[@UCDQ]"
+				if r == qs.innerClose {
+					opener = qs.innerOpen
 				}
-			}
-
-			// open single quote, check if it follows another OSQ
-			// always push
-			if r == "‘" {
-				r2 := Peek()
-				if !sqreport && r2.punc == "‘" {
-					// consecutive OSQ
-					anyreport = true
-					sqreport = true
-					dwbuf[i] = dwbuf[i][:t[0]] + "[@COSQ]" + dwbuf[i][t[0]:]
+				if r == "’" {
+					tag = "[@UCSQ]"
 				}
-				Push(xpuncEvent{r, i, t[0]})
-			}
-
-			// close single quote should be paired with an open single quote on the stack
-			// if so, remove the OSQ on the stack
-			// pop only if expected, else leave stack intact
-			if r == "’" {
 				r2 := Peek()
-				if r2.punc == "‘" { // expected
+				if r2.punc == opener { // expected
 					_ = Pop()
+				} else if r == qs.outerClose && r == "’" && r2.punc == qs.innerOpen {
+					// British style: the outer quote cannot close while an
+					// inner quote is open, so this is an apostrophe
 				} else {
 					anyreport = true
-					dwbuf[i] = dwbuf[i][:t[0]] + "[@UCSQ]" + dwbuf[i][t[0]:]
+					mark(t[0], tag)
 				}
 			}
 		}
@@ -968,130 +1160,879 @@ func puncScan() []string {
 
 /* ********************************************************************** */
 /*                                                                        */
-/* spellcheck based on aspell                                             */
+/* straight to curly quote conversion                                     */
 /*                                                                        */
 /* ********************************************************************** */
 
-func Intersection(a, b []string) (c []string) {
-	m := make(map[string]bool)
+// the context a straight quote is seen in, looking left or right
+const (
+	qctxSpace = iota // start or end of line, white space, brackets, open quotes
+	qctxDash         // any dash
+	qctxWord         // letters, digits and other punctuation
+)
 
-	for _, item := range a {
-		m[item] = true
+func quoteContext(r rune, atEdge bool) int {
+	switch {
+	case atEdge || unicode.IsSpace(r) || strings.ContainsRune("([{“‘_", r):
+		return qctxSpace
+	case unicode.Is(unicode.Pd, r):
+		return qctxDash
 	}
+	return qctxWord
+}
 
-	for _, item := range b {
-		if _, ok := m[item]; ok {
-			c = append(c, item)
+// curlQuotes writes curled.txt, a copy of the text with straight quotes
+// converted to curly quotes, and curlreport.txt, a list of every
+// conversion that could not be decided safely. undecided quotes get a
+// best guess followed by "[**?]" in curled.txt so they can be found.
+// returns a summary for the user.
+func curlQuotes() []string {
+	cb := make([]string, len(wbuf)) // converted text
+	crs := []string{}               // report lines
+	nconv, namb := 0, 0
+
+	// single and double quotes used as the outer quotes
+	outerSingle := puncStyle == "British"
+
+	// leading apostrophes: common words and h-dropping ('ouse) accepted by aspell
+	reLead := regexp.MustCompile(`(?i)^(` + leadingApostropheWords + `)($|\P{L})`)
+	reTrail := regexp.MustCompile(`(?i)(^|\P{L})(` + trailingApostropheWords + `)$`)
+	reHdrop := regexp.MustCompile(`(^|\P{L})'(\p{L}+)`)
+	hwords := make(map[string]int)
+	for _, line := range wbuf {
+		for _, u := range reHdrop.FindAllStringSubmatch(line, -1) {
+			hwords["h"+strings.ToLower(u[2])] = 1
 		}
 	}
-	return
-}
+	hwords = asqual(hwords)
 
-// $ aspell --help  shows installed languages
-// # apt install aspell  installs aspell and language "en"
-// # apt install aspell-es  installs addtl. language
+	// contractions in the good word list, with straight apostrophes
+	gwre := []*regexp.Regexp{}
+	for _, word := range goodWordsFor("") {
+		if strings.Contains(word, "’") {
+			sword := regexp.QuoteMeta(strings.Replace(word, "’", "'", -1))
+			gwre = append(gwre, regexp.MustCompile(`(^|\P{L})`+sword+`($|\P{L})`))
+		}
+	}
 
-func aspellCheck() ([]string, []string, []string) {
+	// word starting at rune position i
+	wordAt := func(rl []rune, i int) string {
+		j := i
+		for j < len(rl) && (unicode.IsLetter(rl[j]) || unicode.IsDigit(rl[j])) {
+			j++
+		}
+		return string(rl[i:j])
+	}
 
-	var sw []string // suspect words
-	okwords := make(map[string]int, len(wordListMapCount))
-	okslice := []string{}
+	ambiguous := func(n int, col int, guess rune, why string) string {
+		namb++
+		crs = append(crs, fmt.Sprintf("  %5d.%-3d %s: %s", n+1, col+1, string(guess), why))
+		crs = append(crs, fmt.Sprintf("            %s", getParaSegment(wbuf[n], len(string([]rune(wbuf[n])[:col])))))
+		return string(guess) + "[**?]"
+	}
 
-	rs := []string{} // empty rs to start aggregation
-	rs = append(rs, "☳<a name='spell'></a>")
+	for start := 0; start < len(wbuf); {
+		// one paragraph at a time
+		if strings.TrimSpace(wbuf[start]) == "" {
+			cb[start] = wbuf[start]
+			start++
+			continue
+		}
+		end := start
+		for end < len(wbuf) && strings.TrimSpace(wbuf[end]) != "" {
+			end++
+		}
+
+		// quotes open in the paragraph, innermost last, with where each
+		// was written in cb
+		type openQuote struct {
+			q         rune
+			n, col    int // line and rune column in the source
+			off       int // byte offset in the converted line
+			undecided bool
+		}
+		stack := []openQuote{}
+		dqOpen, sqOpen := 0, 0 // open double and single quotes
+		push := func(q rune, n, col int, out string) {
+			stack = append(stack, openQuote{q, n, col, len(out) - len(string(q)), strings.HasSuffix(out, "[**?]")})
+			if stack[len(stack)-1].undecided {
+				stack[len(stack)-1].off -= len("[**?]")
+			}
+			if q == '“' {
+				dqOpen++
+			} else {
+				sqOpen++
+			}
+		}
+		// close the innermost quote of a kind. reports whether it was
+		// the innermost of all
+		pop := func(q rune) bool {
+			for k := len(stack) - 1; k >= 0; k-- {
+				if stack[k].q == q {
+					innermost := k == len(stack)-1
+					stack = append(stack[:k], stack[k+1:]...)
+					if q == '“' {
+						dqOpen--
+					} else {
+						sqOpen--
+					}
+					return innermost
+				}
+			}
+			return false
+		}
+		innerIs := func(q rune) bool {
+			return len(stack) > 0 && stack[len(stack)-1].q == q
+		}
 
-	rs = append(rs, "☳"+strings.Repeat("*", 80))
-	rs = append(rs, fmt.Sprintf("* %-76s *", fmt.Sprintf("SPELLCHECK SUSPECT WORDS (%s)", p.Alang)))
-	rs = append(rs, strings.Repeat("*", 80))
-	rs = append(rs, "")
+		// count single quotes that can only close or be apostrophes
+		// in the rest of the paragraph, to pair with an open quote
+		closersAfter := func(n, col int) bool {
+			for ; n < end; n, col = n+1, -1 {
+				rl := []rune(wbuf[n])
+				for j := col + 1; j < len(rl); j++ {
+					if rl[j] == '\'' && j > 0 && quoteContext(rl[j-1], false) == qctxWord &&
+						(j == len(rl)-1 || !unicode.IsLetter(rl[j+1])) {
+						return true
+					}
+				}
+			}
+			return false
+		}
 
-	// previously, the wordListMapCount was populated with all words and how
-	// often they occured. That map is the starting point for a list of good
-	// words in the book. Start with all words, subtract suspects -> result
-	// are good words that will be used later, as in the Levenshtein distance
-	// checks of each suspect word against all good words.
+		for n := start; n < end; n++ {
+			line := wbuf[n]
+			for _, re := range gwre {
+				line = re.ReplaceAllStringFunc(line, func(s string) string {
+					return strings.Replace(s, "'", "’", -1)
+				})
+			}
+			rl := []rune(line)
+			out := ""
+			for i, r := range rl {
+				if r != '"' && r != '\'' {
+					out += string(r)
+					continue
+				}
+				left := qctxSpace
+				if i > 0 {
+					left = quoteContext(rl[i-1], false)
+				}
+				right := qctxSpace
+				if i < len(rl)-1 {
+					right = quoteContext(rl[i+1], false)
+				}
+				nconv++
+
+				if r == '"' {
+					switch {
+					case left == qctxSpace && right != qctxSpace, left == qctxDash && right == qctxWord:
+						if innerIs('“') {
+							out += ambiguous(n, i, '“', "open quote while a quote is open")
+						} else {
+							out += "“"
+						}
+						push('“', n, i, out)
+					case left == qctxWord && right != qctxWord, left == qctxDash && right == qctxSpace:
+						switch {
+						case dqOpen == 0:
+							out += ambiguous(n, i, '”', "close quote with no open quote")
+						case !innerIs('“'):
+							out += ambiguous(n, i, '”', "close quote while a single quote is open")
+							pop('“')
+						default:
+							out += "”"
+							pop('“')
+						}
+					default:
+						// floating ( " ) or embedded (a"b) quote. guess from state
+						if innerIs('“') {
+							out += ambiguous(n, i, '”', "quote direction unclear")
+							pop('“')
+						} else {
+							out += ambiguous(n, i, '“', "quote direction unclear")
+							push('“', n, i, out)
+						}
+					}
+					continue
+				}
 
-	for k, v := range wordListMapCount {
-		okwords[k] = v
+				// single quote
+				switch {
+				case left == qctxWord && right == qctxWord && unicode.IsLetter(rl[i+1]):
+					if unicode.IsLetter(rl[i-1]) || unicode.IsDigit(rl[i-1]) {
+						out += "’" // contraction
+					} else {
+						out += ambiguous(n, i, '’', "quote between punctuation and letter")
+					}
+				case left == qctxWord:
+					// close quote or trailing apostrophe. both are ’
+					out += "’"
+					if sqOpen > 0 && !reTrail.MatchString(string(rl[:i])) {
+						pop('‘')
+					}
+				case left != qctxWord && right == qctxWord:
+					// open quote or leading apostrophe. a single quote
+					// opens only outside or inside a double quote, never
+					// directly inside another single quote
+					w := wordAt(rl, i+1)
+					if reLead.MatchString(string(rl[i+1:])) {
+						out += "’"
+					} else if _, ok := hwords["h"+strings.ToLower(w)]; ok && w != "" {
+						out += "’"
+					} else if !closersAfter(n, i) {
+						out += ambiguous(n, i, '’', "leading apostrophe or open quote with no close")
+					} else if innerIs('‘') {
+						out += ambiguous(n, i, '’', "leading apostrophe or open quote inside a single quote")
+					} else if !outerSingle && dqOpen == 0 {
+						out += ambiguous(n, i, '‘', "open single quote outside double quotes")
+						push('‘', n, i, out)
+					} else if outerSingle && sqOpen > 0 {
+						out += ambiguous(n, i, '‘', "single quote inside a single quote")
+						push('‘', n, i, out)
+					} else {
+						out += "‘"
+						push('‘', n, i, out)
+					}
+				default:
+					out += ambiguous(n, i, '’', "floating single quote")
+				}
+			}
+			cb[n] = out
+		}
+
+		// quotes still open at the end of the paragraph. an outer quote
+		// may stay open if the next paragraph opens with a quote, as in a
+		// quotation running over several paragraphs
+		next := end
+		for next < len(wbuf) && strings.TrimSpace(wbuf[next]) == "" {
+			next++
+		}
+		continues := next < len(wbuf) && strings.HasPrefix(strings.TrimLeft(wbuf[next], " _"), "\"")
+		if outerSingle {
+			continues = next < len(wbuf) && strings.HasPrefix(strings.TrimLeft(wbuf[next], " _"), "'")
+		}
+		for k := len(stack) - 1; k >= 0; k-- {
+			oq := stack[k]
+			if k == 0 && continues && (oq.q == '“') != outerSingle {
+				continue
+			}
+			if oq.undecided {
+				continue // already marked and reported
+			}
+			at := oq.off + len(string(oq.q))
+			cb[oq.n] = cb[oq.n][:at] + "[**?]" + cb[oq.n][at:]
+			namb++
+			crs = append(crs, fmt.Sprintf("  %5d.%-3d %s: %s", oq.n+1, oq.col+1, string(oq.q), "quote not closed by the end of the paragraph"))
+			crs = append(crs, fmt.Sprintf("            %s", getParaSegment(wbuf[oq.n], len(string([]rune(wbuf[oq.n])[:oq.col])))))
+		}
+		start = end
 	}
 
-	// any words in the good word list need to be pulled out of the text *before* running
-	// aspell because aspell will split it. Example: avec-trollop will be flagged for "avec"
-	// this will replace the entire "avec-trollop" with "▷000000◁" which will not flag aspell.
+	// keep the line endings of the source
+	crlf := "\n"
+	if raw, err := os.ReadFile(p.Infile); err == nil && strings.Contains(string(raw), "\r\n") {
+		crlf = "\r\n"
+	}
+	f2, err := os.Create(p.Outdir + "/curled.txt")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, line := range cb {
+		fmt.Fprintf(f2, "%s%s", line, crlf)
+	}
+	f2.Close()
 
-	lwbuf := make([]string, len(wbuf))
-	copy(lwbuf, wbuf)
+	f3, err := os.Create(p.Outdir + "/curlreport.txt")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Fprintf(f3, "%s\n", BOM+"STRAIGHT TO CURLY QUOTE CONVERSION")
+	fmt.Fprintf(f3, "%s-style punctuation. undecided conversions marked \"[**?]\" in curled.txt\n", puncStyle)
+	fmt.Fprintf(f3, "%s\n\n", "-------------------------------------------------------------------")
+	if namb == 0 {
+		fmt.Fprintf(f3, "all %d quotes converted\n", nconv)
+	}
+	for _, line := range crs {
+		fmt.Fprintf(f3, "%s\n", line)
+	}
+	f3.Close()
 
-	for i, line := range lwbuf {
-		for n, word := range goodWordlist {
-			// use the index to generate a token
-			if strings.Contains(line, word) {
-				lwbuf[i] = strings.Replace(lwbuf[i], word, fmt.Sprintf("▷%06d◁", n), -1)
-			}
-		}
+	return []string{
+		fmt.Sprintf("quotes converted: %d", nconv),
+		fmt.Sprintf("undecided: %d (see curlreport.txt)", namb),
+		"converted text saved in curled.txt",
 	}
+}
 
-	// any hyphenated words will be evaluated by parts. either part or both can flag a report.
-	//
-	// begin successive aspell runs for each language
+/* ********************************************************************** */
+/*                                                                        */
+/* safe auto-fix mode                                                     */
+/*                                                                        */
+/* ********************************************************************** */
 
-	// process with each language specified by user
-	uselangs := strings.Split(p.Alang, ",")
-	for _, rl := range uselangs {
-		lwbuf = runAspell(lwbuf, rl)
+// a class of mechanical fixes the user can opt into with -f
+type fixClass struct {
+	name string                   // name used with -f
+	note string                   // description for the transcriber's notes
+	fix  func(line string) string // returns the corrected line
+}
+
+var reFixSpaces = regexp.MustCompile(` {2,}`)
+var reFixDash2 = regexp.MustCompile(`(^|[^-])--([^-]|$)`)
+var reFixDash4 = regexp.MustCompile(`(^|[^-])----([^-]|$)`)
+var reFixEtc = regexp.MustCompile(`&c([^\.]|$)`)
+var reFixPunc = regexp.MustCompile(`(\S) +([\?!:;])`)
+
+var fixClasses = []fixClass{
+	{"trailing", "trailing spaces removed", func(line string) string {
+		return strings.TrimRight(line, " ")
+	}},
+	{"spaces", "adjacent spaces within lines collapsed", func(line string) string {
+		// keep indentation and trailing spaces; those are separate fixes
+		core := strings.TrimSpace(line)
+		if core == "" {
+			return line
+		}
+		lead := line[:strings.Index(line, core)]
+		trail := line[len(lead)+len(core):]
+		return lead + reFixSpaces.ReplaceAllString(core, " ") + trail
+	}},
+	{"bom", "byte order mark removed", nil}, // applies to the file, not to lines
+	{"dash", "\"--\" converted to em-dash", func(line string) string {
+		for i := 0; i < 2; i++ { // twice for "a--b--c"
+			line = reFixDash4.ReplaceAllString(line, "$1——$2")
+			line = reFixDash2.ReplaceAllString(line, "$1—$2")
+		}
+		return line
+	}},
+	{"etc", "period added to \"&c\"", func(line string) string {
+		return reFixEtc.ReplaceAllString(line, "&c.$1")
+	}},
+	{"punct", "space before ? ! : ; removed", func(line string) string {
+		return reFixPunc.ReplaceAllString(line, "$1$2")
+	}},
+	{"scannos", "scannos corrected in context", func(line string) string {
+		for _, sc := range scannoFixList {
+			line = fixScanno(line, sc)
+		}
+		return line
+	}},
+}
+
+// unified diff of two versions of the text with the same number of lines
+func unifiedDiff(a, b []string, aname, bname string) []string {
+	const context = 3
+	rs := []string{"--- " + aname, "+++ " + bname}
+	changed := []int{}
+	for i := range a {
+		if a[i] != b[i] {
+			changed = append(changed, i)
+		}
+	}
+	for k := 0; k < len(changed); {
+		// collect changes close enough to share a hunk
+		first, last := changed[k], changed[k]
+		for k++; k < len(changed) && changed[k]-last <= 2*context; k++ {
+			last = changed[k]
+		}
+		start := first - context
+		if start < 0 {
+			start = 0
+		}
+		end := last + context + 1
+		if end > len(a) {
+			end = len(a)
+		}
+		rs = append(rs, fmt.Sprintf("@@ -%d,%d +%d,%d @@", start+1, end-start, start+1, end-start))
+		for i := start; i < end; {
+			if a[i] == b[i] {
+				rs = append(rs, " "+a[i])
+				i++
+				continue
+			}
+			j := i
+			for j < end && a[j] != b[j] {
+				j++
+			}
+			for n := i; n < j; n++ {
+				rs = append(rs, "-"+a[n])
+			}
+			for n := i; n < j; n++ {
+				rs = append(rs, "+"+b[n])
+			}
+			i = j
+		}
 	}
+	return rs
+}
 
-	suspect_words := lwbuf
+// autoFix applies the fix classes named in -f to a copy of the text.
+// writes fixed.txt, fixed.diff (unified diff against the source file) and
+// fixlog.txt (the fixes applied, for the transcriber's notes). the source
+// file is never changed. returns a summary for the user.
+func autoFix() []string {
+	selected := map[string]bool{}
+	for _, name := range strings.Split(p.Fixes, ",") {
+		selected[strings.TrimSpace(name)] = true
+	}
+	for name := range selected {
+		known := name == "all"
+		for _, fc := range fixClasses {
+			known = known || fc.name == name
+		}
+		if !known {
+			log.Fatalf("unknown fix class: %s", name)
+		}
+	}
 
-	// into slice
-	if len(suspect_words) > 0 {
-		suspect_words = suspect_words[:len(suspect_words)-1]
+	// the source as it is on disk, including any BOM readText removed
+	orig := make([]string, len(wbuf))
+	copy(orig, wbuf)
+	if raw, err := os.ReadFile(p.Infile); err == nil && strings.HasPrefix(string(raw), BOM) && len(orig) > 0 {
+		orig[0] = BOM + orig[0]
 	}
+	fixed := make([]string, len(orig))
+	copy(fixed, orig)
 
-	// reduce suspect using various rules
-	i := 0
-	for ; i < len(suspect_words); i++ {
-		t := suspect_words[i]
+	scannoFixList = nil
+	for _, sc := range scannoList {
+		if sc.fix != "" && sc.ctx != nil && !inGoodWordList(sc.word, "scanno") {
+			scannoFixList = append(scannoFixList, sc)
+		}
+	}
 
-		// if word occurs 5 or more times, accept it
-		if wordListMapCount[strings.ToLower(t)]+
-			wordListMapCount[strings.Title(strings.ToLower(t))]+
-			wordListMapCount[strings.ToUpper(t)]+
-			wordListMapCount[t] >= 5 {
-			suspect_words = append(suspect_words[:i], suspect_words[i+1:]...)
-			i--
+	flog := []string{}    // summary of fixes applied
+	fdetail := []string{} // each line changed
+	for _, fc := range fixClasses {
+		if !selected["all"] && !selected[fc.name] {
 			continue
 		}
-		// danger: this could hide a " th " type by accepting "4th"
-		if t == "th" || t == "st" || t == "nd" {
-			suspect_words = append(suspect_words[:i], suspect_words[i+1:]...)
-			i--
+		count := 0
+		if fc.name == "dash" && dashStyle == "ASCII" {
+			flog = append(flog, fmt.Sprintf("  \"--\" kept: dash convention is ASCII (-e %s)", p.Dashes))
 			continue
 		}
+		if fc.name == "bom" {
+			if len(fixed) > 0 && strings.HasPrefix(fixed[0], BOM) {
+				fixed[0] = strings.TrimPrefix(fixed[0], BOM)
+				count++
+				fdetail = append(fdetail, fmt.Sprintf("  %-9s %5d: (byte order mark)", fc.name, 1))
+			}
+		} else {
+			for i, line := range fixed {
+				if t := fc.fix(line); t != line {
+					fixed[i] = t
+					count++
+					fdetail = append(fdetail, fmt.Sprintf("  %-9s %5d: %s", fc.name, i+1, t))
+				}
+			}
+		}
+		if count > 0 {
+			flog = append(flog, fmt.Sprintf("  %s: %d", fc.note, count))
+		}
 	}
 
-	// sort the list of suspect words for report order
-	sort.Slice(suspect_words, func(i, j int) bool {
-		return strings.ToLower(suspect_words[i]) < strings.ToLower(suspect_words[j])
-	})
+	crlf := "\n"
+	if raw, err := os.ReadFile(p.Infile); err == nil && strings.Contains(string(raw), "\r\n") {
+		crlf = "\r\n"
+	}
+	f2, err := os.Create(p.Outdir + "/fixed.txt")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, line := range fixed {
+		fmt.Fprintf(f2, "%s%s", line, crlf)
+	}
+	f2.Close()
 
-	// show each word in context
+	f3, err := os.Create(p.Outdir + "/fixed.diff")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, line := range unifiedDiff(orig, fixed, path.Base(p.Infile), "fixed.txt") {
+		fmt.Fprintf(f3, "%s\n", line)
+	}
+	f3.Close()
 
-	// lcreported := make(map[string]int) // map to hold downcased words reported
+	f4, err := os.Create(p.Outdir + "/fixlog.txt")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Fprintf(f4, "fixes applied to %s:\n", path.Base(p.Infile))
+	if len(flog) == 0 {
+		fmt.Fprintf(f4, "  none\n")
+	}
+	for _, line := range flog {
+		fmt.Fprintf(f4, "%s\n", line)
+	}
+	fmt.Fprintf(f4, "\nlines changed:\n")
+	for _, line := range fdetail {
+		fmt.Fprintf(f4, "%s\n", line)
+	}
+	f4.Close()
 
-	// rs = append(rs, fmt.Sprintf("Suspect words:"))
-	re4 := regexp.MustCompile(`☰`)
+	rs := []string{"fixes applied:"}
+	if len(flog) == 0 {
+		rs = append(rs, "  none")
+	}
+	rs = append(rs, flog...)
+	rs = append(rs, "corrected text saved in fixed.txt, changes in fixed.diff and fixlog.txt")
+	return rs
+}
 
-	for _, word := range suspect_words {
-		/*
-			// if I've reported the word in any case, don't report it again
-			lcword := strings.ToLower(word)
-			if _, ok := lcreported[lcword]; ok { // if it is there already, skip
-				continue
-			} else {
-				lcreported[lcword] = 1
-			}
-		*/
+/* ********************************************************************** */
+/*                                                                        */
+/* spellcheck based on aspell                                             */
+/*                                                                        */
+/* ********************************************************************** */
+
+func Intersection(a, b []string) (c []string) {
+	m := make(map[string]bool)
+
+	for _, item := range a {
+		m[item] = true
+	}
+
+	for _, item := range b {
+		if _, ok := m[item]; ok {
+			c = append(c, item)
+		}
+	}
+	return
+}
+
+// character trigram language identification
+//
+// with more than one aspell language, each paragraph and each quoted
+// passage is tagged with the language whose trigram profile fits it best.
+// the profiles are built from the aspell dictionaries themselves.
+
+// trigram counts for one language
+type langProfile struct {
+	lang  string
+	tri   map[string]int
+	total int
+}
+
+// a passage of the text in a language other than the first one given
+type langRegion struct {
+	lnum int    // line the passage starts on, 1-based
+	lang string // language of the passage
+	text string // the passage
+}
+
+var reLangWord *regexp.Regexp = regexp.MustCompile(`\p{L}+(’\p{L}+)*`)
+var reLangQuote *regexp.Regexp = regexp.MustCompile(`“[^“”]+”|"[^"]+"`)
+
+// lower case trigrams of the words in s. each word is padded with spaces
+// so the beginnings and ends of words count
+func trigrams(s string) []string {
+	tris := []string{}
+	for _, w := range reLangWord.FindAllString(strings.ToLower(s), -1) {
+		rw := []rune(" " + w + " ")
+		for i := 0; i+3 <= len(rw); i++ {
+			tris = append(tris, string(rw[i:i+3]))
+		}
+	}
+	return tris
+}
+
+// trigram profile of a language from the words of its aspell dictionary
+func buildLangProfile(lang string) (langProfile, error) {
+	lp := langProfile{lang, make(map[string]int), 0}
+	out, err := exec.Command("/usr/bin/aspell", "--encoding", "utf-8", "--lang", lang, "dump", "master").Output()
+	if err != nil {
+		return lp, err
+	}
+	for _, word := range strings.Split(string(out), "\n") {
+		word = strings.Split(word, "/")[0] // drop any affix flags
+		for _, t := range trigrams(word) {
+			lp.tri[t]++
+			lp.total++
+		}
+	}
+	if lp.total == 0 {
+		return lp, fmt.Errorf("empty dictionary for %s", lang)
+	}
+	return lp, nil
+}
+
+var langProfiles []langProfile // profiles of the -a languages, once built
+var langProfilesErr error
+var langProfilesBuilt bool
+
+// the trigram profiles of the -a languages, built on first use. nil with
+// only one language or if a profile could not be built
+func languageProfiles() ([]langProfile, error) {
+	if langProfilesBuilt {
+		return langProfiles, langProfilesErr
+	}
+	langProfilesBuilt = true
+	uselangs := strings.Split(p.Alang, ",")
+	if len(uselangs) < 2 {
+		return nil, nil
+	}
+	for _, rl := range uselangs {
+		lp, err := buildLangProfile(rl)
+		if err != nil {
+			langProfiles, langProfilesErr = nil, fmt.Errorf("no language profile for %s", rl)
+			return langProfiles, langProfilesErr
+		}
+		langProfiles = append(langProfiles, lp)
+	}
+	return langProfiles, nil
+}
+
+// log likelihood of the trigrams under a profile. a trigram the language
+// does not have gets the same small probability in every profile, so a
+// large dictionary is not penalized for its size
+func (lp langProfile) score(tris []string) float64 {
+	const unseen = 1e-6
+	sc := 0.0
+	for _, t := range tris {
+		pr := float64(lp.tri[t]) / float64(lp.total)
+		if pr < unseen {
+			pr = unseen
+		}
+		sc += math.Log(pr)
+	}
+	return sc
+}
+
+// language of s. too little text to tell returns deflang
+func identifyLang(s string, profiles []langProfile, deflang string) string {
+	if len(reLangWord.FindAllString(s, -1)) < 3 {
+		return deflang
+	}
+	tris := trigrams(s)
+	best, bestscore := deflang, math.Inf(-1)
+	for _, lp := range profiles {
+		if sc := lp.score(tris); sc > bestscore {
+			best, bestscore = lp.lang, sc
+		}
+	}
+	return best
+}
+
+// tag each paragraph, and each quoted passage within it, with a language.
+// returns a copy of the lines for each language with the text in other
+// languages blanked out, and the passages not in the first language
+func tagLanguages(lines []string, profiles []langProfile) (map[string][]string, []langRegion) {
+	masked := make(map[string][]string)
+	for _, lp := range profiles {
+		masked[lp.lang] = make([]string, 0, len(lines))
+	}
+	regions := []langRegion{}
+	mainlang := profiles[0].lang
+
+	for start := 0; start < len(lines); {
+		end := start // paragraph is lines[start:end]
+		for end < len(lines) && strings.TrimSpace(lines[end]) != "" {
+			end++
+		}
+		if end == start {
+			end++ // blank line
+		}
+		para := strings.Join(lines[start:end], "\n")
+
+		// language of each byte of the paragraph
+		plang := identifyLang(reLangQuote.ReplaceAllString(para, " "), profiles, mainlang)
+		bytelang := make([]string, len(para))
+		for i := range bytelang {
+			bytelang[i] = plang
+		}
+		if plang != mainlang {
+			regions = append(regions, langRegion{start + 1, plang, para})
+		}
+		for _, loc := range reLangQuote.FindAllStringIndex(para, -1) {
+			qlang := identifyLang(para[loc[0]:loc[1]], profiles, plang)
+			for i := loc[0]; i < loc[1]; i++ {
+				bytelang[i] = qlang
+			}
+			if qlang != plang && qlang != mainlang {
+				lnum := start + strings.Count(para[:loc[0]], "\n") + 1
+				regions = append(regions, langRegion{lnum, qlang, para[loc[0]:loc[1]]})
+			}
+		}
+
+		for _, lp := range profiles {
+			b := []byte(para)
+			for i := range b {
+				if bytelang[i] != lp.lang && b[i] != '\n' {
+					b[i] = ' '
+				}
+			}
+			masked[lp.lang] = append(masked[lp.lang], strings.Split(string(b), "\n")...)
+		}
+		start = end
+	}
+	return masked, regions
+}
+
+// report the passages not in the first language, grouped by language
+func langReport(regions []langRegion, langs []string) []string {
+	rs := []string{}
+	rs = append(rs, "----- foreign language passages ----------------------------------------------")
+	rs = append(rs, "")
+	for _, lang := range langs[1:] {
+		count := 0
+		for _, rg := range regions {
+			if rg.lang != lang {
+				continue
+			}
+			if count == 0 {
+				rs = append(rs, lang)
+			}
+			if count < 5 || p.Verbose {
+				passage := strings.Join(strings.Fields(rg.text), " ")
+				if rp := []rune(passage); len(rp) > 70 {
+					passage = string(rp[:70]) + "…"
+				}
+				rs = append(rs, fmt.Sprintf("  %5d: %s", rg.lnum, passage))
+			}
+			count++
+		}
+		if !p.Verbose && count > 5 {
+			rs = append(rs, fmt.Sprintf("         ... %d more", count-5))
+		}
+	}
+	if len(rs) == 2 {
+		rs = append(rs, fmt.Sprintf("  no passages found in %s.", strings.Join(langs[1:], ", ")))
+	}
+	rs = append(rs, "")
+	return rs
+}
+
+// $ aspell --help  shows installed languages
+// # apt install aspell  installs aspell and language "en"
+// # apt install aspell-es  installs addtl. language
+
+func aspellCheck() ([]string, []string, []string) {
+
+	var sw []string // suspect words
+	okwords := make(map[string]int, len(wordListMapCount))
+	okslice := []string{}
+
+	rs := []string{} // empty rs to start aggregation
+	rs = append(rs, "☳<a name='spell'></a>")
+
+	rs = append(rs, "☳"+strings.Repeat("*", 80))
+	rs = append(rs, fmt.Sprintf("* %-76s *", fmt.Sprintf("SPELLCHECK SUSPECT WORDS (%s)", p.Alang)))
+	rs = append(rs, strings.Repeat("*", 80))
+	rs = append(rs, "")
+
+	// previously, the wordListMapCount was populated with all words and how
+	// often they occured. That map is the starting point for a list of good
+	// words in the book. Start with all words, subtract suspects -> result
+	// are good words that will be used later, as in the Levenshtein distance
+	// checks of each suspect word against all good words.
+
+	for k, v := range wordListMapCount {
+		okwords[k] = v
+	}
+
+	// any words in the good word list need to be pulled out of the text *before* running
+	// aspell because aspell will split it. Example: avec-trollop will be flagged for "avec"
+	// this will replace the entire "avec-trollop" with "▷000000◁" which will not flag aspell.
+
+	// Greek transliterations are not checked as English
+	lwbuf := maskGreek(wbuf, findGreekBlocks(wbuf))
+
+	for i, line := range lwbuf {
+		for n, gw := range goodWordlist {
+			if gw.scope != "" && gw.scope != "spell" {
+				continue
+			}
+			// literal entries can be skipped cheaply
+			if !gw.regex && !strings.Contains(line, gw.word) {
+				continue
+			}
+			// use the index to generate a token. twice for adjacent matches
+			token := fmt.Sprintf("${gwpre}▷%06d◁${gwpost}", n)
+			lwbuf[i] = gw.re.ReplaceAllString(lwbuf[i], token)
+			lwbuf[i] = gw.re.ReplaceAllString(lwbuf[i], token)
+		}
+	}
+
+	// any hyphenated words will be evaluated by parts. either part or both can flag a report.
+	//
+	// begin successive aspell runs for each language
+
+	// process with each language specified by user. with more than one
+	// language, each passage is checked only against its own language
+	uselangs := strings.Split(p.Alang, ",")
+	profiles, err := languageProfiles()
+	if err != nil {
+		rs = append(rs, fmt.Sprintf("%v: all languages check all text", err))
+		rs = append(rs, "")
+	}
+	if len(profiles) > 1 {
+		masked, regions := tagLanguages(lwbuf, profiles)
+		rs = append(rs, langReport(regions, uselangs)...)
+		words := []string{}
+		for _, rl := range uselangs {
+			words = append(words, runAspell(masked[rl], rl)...)
+		}
+		lwbuf = uniqueStrings(words)
+	} else {
+		for _, rl := range uselangs {
+			lwbuf = runAspell(lwbuf, rl)
+		}
+	}
+
+	// into slice, without the empty line(s) from aspell
+	suspect_words := []string{}
+	for _, word := range lwbuf {
+		if word != "" {
+			suspect_words = append(suspect_words, word)
+		}
+	}
+
+	// reduce suspect using various rules
+	i := 0
+	for ; i < len(suspect_words); i++ {
+		t := suspect_words[i]
+
+		// if word occurs 5 or more times, accept it
+		if wordListMapCount[strings.ToLower(t)]+
+			wordListMapCount[strings.Title(strings.ToLower(t))]+
+			wordListMapCount[strings.ToUpper(t)]+
+			wordListMapCount[t] >= 5 {
+			suspect_words = append(suspect_words[:i], suspect_words[i+1:]...)
+			i--
+			continue
+		}
+		// danger: this could hide a " th " type by accepting "4th"
+		if t == "th" || t == "st" || t == "nd" {
+			suspect_words = append(suspect_words[:i], suspect_words[i+1:]...)
+			i--
+			continue
+		}
+	}
+
+	// sort the list of suspect words for report order
+	sort.Slice(suspect_words, func(i, j int) bool {
+		return strings.ToLower(suspect_words[i]) < strings.ToLower(suspect_words[j])
+	})
+
+	// show each word in context
+
+	// lcreported := make(map[string]int) // map to hold downcased words reported
+
+	// rs = append(rs, fmt.Sprintf("Suspect words:"))
+	re4 := regexp.MustCompile(`☰`)
+
+	for _, word := range suspect_words {
+		/*
+			// if I've reported the word in any case, don't report it again
+			lcword := strings.ToLower(word)
+			if _, ok := lcreported[lcword]; ok { // if it is there already, skip
+				continue
+			} else {
+				lcreported[lcword] = 1
+			}
+		*/
 
 		sw = append(sw, word)                    // simple slice of only the word
 		rs = append(rs, fmt.Sprintf("%s", word)) // word we will show in context
@@ -1180,17 +2121,32 @@ func aspellCheck() ([]string, []string, []string) {
 /*                                                                        */
 /* ********************************************************************** */
 
-// compare the good word list to the submitted word
+// compare the good word list entries for a check to the submitted word.
+// entries match whole words, so "Ann" does not match "Annex", but
 // allow variations, i.e. "Rose-Ann" in GWL will match "Rose-Ann’s"
-func inGoodWordList(s string) bool {
-	for _, word := range goodWordlist {
-		if strings.Contains(s, word) {
+func inGoodWordList(s string, check string) bool {
+	for _, gw := range goodWordlist {
+		if gw.scope != "" && gw.scope != check {
+			continue
+		}
+		if gw.re.MatchString(s) {
 			return true
 		}
 	}
 	return false
 }
 
+// the literal words in the good word list that apply to a check
+func goodWordsFor(check string) []string {
+	words := []string{}
+	for _, gw := range goodWordlist {
+		if !gw.regex && (gw.scope == "" || gw.scope == check) {
+			words = append(words, gw.word)
+		}
+	}
+	return words
+}
+
 type rp struct {
 	rpr rune
 	rpp int
@@ -1258,10 +2214,10 @@ func getPuncStyle() string {
 	// decide is this is American or British punctuation
 	cbrit, camer := 0, 0
 	for _, line := range wbuf {
-		if strings.Contains(line, ".’") {
+		if strings.Contains(line, ".’") || strings.Contains(line, ".'") {
 			cbrit += 1
 		}
-		if strings.Contains(line, ".”") {
+		if strings.Contains(line, ".”") || strings.Contains(line, ".\"") {
 			camer += 1
 		}
 	}
@@ -1272,6 +2228,31 @@ func getPuncStyle() string {
 	}
 }
 
+// decide if the book writes an em-dash as "--" or as a Unicode dash,
+// unless the user has said which
+func getDashStyle() string {
+	switch p.Dashes {
+	case "ascii":
+		return "ASCII"
+	case "unicode":
+		return "Unicode"
+	}
+	cascii, cunicode := 0, 0
+	for _, line := range wbuf {
+		if reFixDash2.MatchString(line) {
+			cascii += 1
+		}
+		if strings.Contains(line, "—") {
+			cunicode += 1
+		}
+	}
+	if cascii > cunicode {
+		return "ASCII"
+	} else {
+		return "Unicode"
+	}
+}
+
 // Pretty print variable (struct, map, array, slice) in Golang.
 
 func prettyPrint(v interface{}) (err error) {
@@ -1507,24 +2488,25 @@ func scannoCheck(wb []string) []string {
 	rs = append(rs, "")
 
 	count := 0
-	for _, scannoword := range scannoWordlist { // each scanno candidate
+	for _, sc := range scannoList { // each scanno candidate
 		ast := 0
 		// if user has put the word in the good word list, do not search for
 		// it as a scanno
-		if contains(goodWordlist, scannoword) {
+		if inGoodWordList(sc.word, "scanno") {
 			continue
 		}
 		for n, linewords := range lwl { // slice of slices of words per line
 			for _, word := range linewords { // each word on line
-				if word == scannoword {
-					if ast == 0 {
+				if word == sc.word {
+					if ast == 0 && sc.fix != "" {
+						rs = append(rs, fmt.Sprintf("%s → %s", word, sc.fix))
+					} else if ast == 0 {
 						rs = append(rs, fmt.Sprintf("%s", word))
 					}
 					if ast < 5 || p.Verbose {
 						line := wb[n]
-						re := regexp.MustCompile(`(^|\P{L})(` + word + `)(\P{L}|$)`)
-						line = re.ReplaceAllString(line, `$1☰$2☷$3`)
-						re = regexp.MustCompile(`☰`)
+						line = sc.re.ReplaceAllString(line, `$1☰$2☷$3`)
+						re := regexp.MustCompile(`☰`)
 						loc := re.FindStringIndex(line)
 						line = getParaSegment(line, loc[0])
 						rs = append(rs, fmt.Sprintf("  %5d: %s", n+1, line)) // 1=based
@@ -1618,6 +2600,10 @@ These are dash characters I will flag
 
 func tcDashCheck(wb []string, pb []string) []string {
 
+	if dashStyle == "ASCII" {
+		return tcAsciiDashCheck(wb)
+	}
+
 	rs := []string{}
 	rs = append(rs, "----- dash check -------------------------------------------------------------")
 	rs = append(rs, "")
@@ -1735,7 +2721,7 @@ func tcDashCheck(wb []string, pb []string) []string {
 				countdd++
 			}
 			if countdd == 5 {
-				rs = append(rs, "          [book uses \"--\" as em-dash. not reporting further]")
+				rs = append(rs, "          [book uses \"--\" as em-dash (see -e). not reporting further]")
 			}
 			if countdd < 5 || !strings.Contains(s, "--") {
 				thisReportCount++
@@ -1857,6 +2843,93 @@ func tcDashCheck(wb []string, pb []string) []string {
 	return rs
 }
 
+// dash check for books that write an em-dash as "--" and a long dash
+// as "----". hyphen runs of other lengths, spaced "--" and any Unicode
+// dash are reported. eight or more hyphens are a separator line
+func tcAsciiDashCheck(wb []string) []string {
+	rs := []string{}
+	rs = append(rs, "----- dash check (ASCII \"--\" convention) -------------------------------------")
+	rs = append(rs, "")
+
+	reRun := regexp.MustCompile(`-+`)
+
+	type dashGroup struct {
+		name  string
+		lines []string
+	}
+	groups := []dashGroup{
+		{"spaced \"--\":", nil},
+		{"hyphen standing alone:", nil},
+		{"three hyphens:", nil},
+		{"five to seven hyphens:", nil},
+		{"Unicode dashes:", nil},
+	}
+
+	count := 0
+	for i, line := range wb {
+		found := [5]bool{}
+		for _, loc := range reRun.FindAllStringIndex(line, -1) {
+			// a space between the run and more text on the line
+			before := loc[0] > 0 && line[loc[0]-1] == ' ' && strings.TrimSpace(line[:loc[0]]) != ""
+			after := loc[1] < len(line) && line[loc[1]] == ' ' && strings.TrimSpace(line[loc[1]:]) != ""
+			switch loc[1] - loc[0] {
+			case 1:
+				alone := (loc[0] == 0 || line[loc[0]-1] == ' ') && (loc[1] == len(line) || line[loc[1]] == ' ')
+				found[1] = found[1] || alone
+			case 2:
+				found[0] = found[0] || before || after
+			case 3:
+				found[2] = true
+			case 5, 6, 7:
+				found[3] = true
+			}
+		}
+		names := []string{}
+		for _, c := range line {
+			if c != '-' && unicode.Is(unicode.Pd, c) && !contains(names, runeName(c)) {
+				names = append(names, runeName(c))
+			}
+		}
+		found[4] = len(names) > 0
+		for n := range found {
+			if !found[n] {
+				continue
+			}
+			s := fmt.Sprintf("  %6d: %s", i+1, pt(wb[i]))
+			if n == 4 {
+				s += " (" + strings.ToLower(strings.Join(names, ", ")) + ")"
+			}
+			groups[n].lines = append(groups[n].lines, s)
+			count++
+		}
+	}
+
+	for _, g := range groups {
+		if len(g.lines) == 0 {
+			continue
+		}
+		rs = append(rs, "  "+g.name)
+		for n, s := range g.lines {
+			if !p.Verbose && n == 10 {
+				rs = append(rs, fmt.Sprintf("     ... %d more", len(g.lines)-10))
+				break
+			}
+			rs = append(rs, s)
+		}
+	}
+
+	if count == 0 {
+		rs = append(rs, "  no dash suspects found in text.")
+		rs[0] = "☲" + rs[0] // style dim
+	} else {
+		rs[0] = "☳" + rs[0] // style black
+	}
+	rs = append(rs, "")
+	rs[len(rs)-1] += "☷" // close style
+
+	return rs
+}
+
 // ellipsis checks
 func tcEllipsisCheck(wb []string) []string {
 	rs := []string{}
@@ -2084,14 +3157,138 @@ func tcDuplicateLines(wb []string) []string {
 	return rs
 }
 
-// definitions from Project Gutenberg
-const (
-	SHORTEST_PG_LINE = 55
-	LONGEST_PG_LINE  = 75
-	WAY_TOO_LONG     = 80
-)
+// proofing artifacts check
+//   - single-line leftovers from page scans and proofing: page separators,
+//     proofer notes, blank page markers, lines that are only a page number
+//     and short upper-case lines (running heads) repeated through the book
+//   - a running head carries a page number or repeats mostly at page
+//     intervals, so speaker names in a play or a "THE END" after each
+//     story are not one
+//   - header blocks found by tcSpacingCheck are not reported as page
+//     numbers or running heads
+func tcArtifacts(wb []string) []string {
+	rs := []string{}
+	rs = append(rs, "----- proofing artifacts check ------------------------------------------------")
+	rs = append(rs, "")
 
-// short line:
+	re1 := regexp.MustCompile(`^-+File:`)                      // -----File: 0123.png-----
+	re2 := regexp.MustCompile(`\[\*\*`)                        // [**proofer note]
+	re3 := regexp.MustCompile(`(?i)\[\s*blank\s+page\s*\]`)    // [Blank Page]
+	re4 := regexp.MustCompile(`^\[?(\d{1,4}|[ivxlc]+)\]?\.?$`) // 123, [123], xii
+	re5 := regexp.MustCompile(`^\d{1,4}\s+|\s+\d{1,4}$`)       // page number on a running head
+	re6 := regexp.MustCompile(`\p{L}`)                         // has some letters
+
+	// every line of a header block, which runs to the next blank line
+	isHeader := make(map[int]bool)
+	for _, h := range bookHeaders {
+		for n := h.lnum - 1; n >= 0 && n < len(wb) && strings.TrimSpace(wb[n]) != ""; n++ {
+			isHeader[n] = true
+		}
+	}
+
+	kinds := []string{"page separator", "proofer note", "blank page marker", "page number", "running head"}
+	found := make(map[string][]int) // kind -> 0-based line numbers
+
+	// candidate running heads, with page numbers stripped, where they occur
+	// and how many times with a page number
+	heads := make(map[string][]int)
+	numbered := make(map[string]int)
+
+	for n, line := range wb {
+		t := strings.TrimSpace(line)
+		if t == "" {
+			continue
+		}
+		if re1.MatchString(t) {
+			found["page separator"] = append(found["page separator"], n)
+			continue
+		}
+		if re2.MatchString(t) {
+			found["proofer note"] = append(found["proofer note"], n)
+		}
+		if re3.MatchString(t) {
+			found["blank page marker"] = append(found["blank page marker"], n)
+		}
+		if isHeader[n] {
+			continue
+		}
+		if u := re4.FindStringSubmatch(t); u != nil {
+			_, err := strconv.Atoi(u[1])
+			if _, ok := romanValue(u[1]); ok || err == nil {
+				found["page number"] = append(found["page number"], n)
+				continue
+			}
+		}
+		h := re5.ReplaceAllString(t, "")
+		if re6.MatchString(h) && strings.ToUpper(h) == h && utf8.RuneCountInString(h) <= 40 {
+			heads[h] = append(heads[h], n)
+			if h != t {
+				numbered[h]++
+			}
+		}
+	}
+
+	// a running head repeats at least three times, and either carries a
+	// page number at least twice or is mostly a page or two apart
+	const pageMin, pageMax = 20, 120 // lines between heads on one or two pages
+	headKeys := []string{}
+	for h, where := range heads {
+		if len(where) < 3 {
+			continue
+		}
+		paged := 0 // gaps the size of a page or two
+		for i := 1; i < len(where); i++ {
+			if gap := where[i] - where[i-1]; gap >= pageMin && gap <= pageMax {
+				paged++
+			}
+		}
+		if 2*paged > len(where)-1 || numbered[h] >= 2 {
+			headKeys = append(headKeys, h)
+		}
+	}
+	sort.Strings(headKeys)
+	for _, h := range headKeys {
+		found["running head"] = append(found["running head"], heads[h]...)
+	}
+
+	count := 0
+	for _, kind := range kinds {
+		where := found[kind]
+		if len(where) == 0 {
+			continue
+		}
+		rs = append(rs, fmt.Sprintf("  %s (%d):", kind, len(where)))
+		for i, n := range where {
+			if !p.Verbose && i == 5 {
+				rs = append(rs, fmt.Sprintf("         ... %d more", len(where)-5))
+				break
+			}
+			rs = append(rs, fmt.Sprintf("  %5d: %s", n+1, pt(wb[n]))) // 1=based
+		}
+		rs = append(rs, "")
+		count += len(where)
+	}
+
+	if count == 0 {
+		rs = append(rs, "  no proofing artifacts found in text.")
+		rs[0] = "☲" + rs[0] // style dim
+	} else {
+		rs[0] = "☳" + rs[0] // style black
+	}
+	rs = append(rs, "")
+	rs[len(rs)-1] += "☷" // close style
+	tcec += count
+	return rs
+}
+
+// definitions from Project Gutenberg
+const (
+	SHORTEST_PG_LINE = 55
+	LONGEST_PG_LINE  = 75
+	WAY_TOO_LONG     = 80
+)
+
+// short line:
 // this line has no leading space, has some text, length of line less than
 // 55 characters, following line has some text.
 // all lengths count runes
@@ -2358,269 +3555,1730 @@ func tcLetterChecks(wb []string) []string {
 	return rs
 }
 
-// spacing check
-// any spacing is okay until the first 4-space gap. Then
-// expecting 4-1-2 or 4-2 variations only.
-// also captures headers (chapter/h2) in a list to output.
-func tcSpacingCheck(wb []string) []string {
-	rs := []string{}
-	s := ""
-	rs = append(rs, "----- spacing pattern ---------------------------------------------------------")
-	rs = append(rs, "")
-
-	re1 := regexp.MustCompile(`11+1`)
-	re2a := regexp.MustCompile(`3`)
-	re2b := regexp.MustCompile(`5`)
-	re2c := regexp.MustCompile(`22`)
-	re2d := regexp.MustCompile(`44`)
+// a range of code points
+type runeRange struct {
+	lo, hi rune
+}
 
-	consec := 0 // consecutive blank lines
-	lastn := 0  // line number of last paragraph start
+// Unicode blocks, from the Unicode Character Database 14.0.0 Blocks.txt
+var unicodeBlocks = []struct {
+	lo, hi rune
+	name   string
+}{
+	{0x0000, 0x007F, "Basic Latin"},
+	{0x0080, 0x00FF, "Latin-1 Supplement"},
+	{0x0100, 0x017F, "Latin Extended-A"},
+	{0x0180, 0x024F, "Latin Extended-B"},
+	{0x0250, 0x02AF, "IPA Extensions"},
+	{0x02B0, 0x02FF, "Spacing Modifier Letters"},
+	{0x0300, 0x036F, "Combining Diacritical Marks"},
+	{0x0370, 0x03FF, "Greek and Coptic"},
+	{0x0400, 0x04FF, "Cyrillic"},
+	{0x0500, 0x052F, "Cyrillic Supplement"},
+	{0x0530, 0x058F, "Armenian"},
+	{0x0590, 0x05FF, "Hebrew"},
+	{0x0600, 0x06FF, "Arabic"},
+	{0x0700, 0x074F, "Syriac"},
+	{0x0750, 0x077F, "Arabic Supplement"},
+	{0x0780, 0x07BF, "Thaana"},
+	{0x07C0, 0x07FF, "NKo"},
+	{0x0800, 0x083F, "Samaritan"},
+	{0x0840, 0x085F, "Mandaic"},
+	{0x0860, 0x086F, "Syriac Supplement"},
+	{0x0870, 0x089F, "Arabic Extended-B"},
+	{0x08A0, 0x08FF, "Arabic Extended-A"},
+	{0x0900, 0x097F, "Devanagari"},
+	{0x0980, 0x09FF, "Bengali"},
+	{0x0A00, 0x0A7F, "Gurmukhi"},
+	{0x0A80, 0x0AFF, "Gujarati"},
+	{0x0B00, 0x0B7F, "Oriya"},
+	{0x0B80, 0x0BFF, "Tamil"},
+	{0x0C00, 0x0C7F, "Telugu"},
+	{0x0C80, 0x0CFF, "Kannada"},
+	{0x0D00, 0x0D7F, "Malayalam"},
+	{0x0D80, 0x0DFF, "Sinhala"},
+	{0x0E00, 0x0E7F, "Thai"},
+	{0x0E80, 0x0EFF, "Lao"},
+	{0x0F00, 0x0FFF, "Tibetan"},
+	{0x1000, 0x109F, "Myanmar"},
+	{0x10A0, 0x10FF, "Georgian"},
+	{0x1100, 0x11FF, "Hangul Jamo"},
+	{0x1200, 0x137F, "Ethiopic"},
+	{0x1380, 0x139F, "Ethiopic Supplement"},
+	{0x13A0, 0x13FF, "Cherokee"},
+	{0x1400, 0x167F, "Unified Canadian Aboriginal Syllabics"},
+	{0x1680, 0x169F, "Ogham"},
+	{0x16A0, 0x16FF, "Runic"},
+	{0x1700, 0x171F, "Tagalog"},
+	{0x1720, 0x173F, "Hanunoo"},
+	{0x1740, 0x175F, "Buhid"},
+	{0x1760, 0x177F, "Tagbanwa"},
+	{0x1780, 0x17FF, "Khmer"},
+	{0x1800, 0x18AF, "Mongolian"},
+	{0x18B0, 0x18FF, "Unified Canadian Aboriginal Syllabics Extended"},
+	{0x1900, 0x194F, "Limbu"},
+	{0x1950, 0x197F, "Tai Le"},
+	{0x1980, 0x19DF, "New Tai Lue"},
+	{0x19E0, 0x19FF, "Khmer Symbols"},
+	{0x1A00, 0x1A1F, "Buginese"},
+	{0x1A20, 0x1AAF, "Tai Tham"},
+	{0x1AB0, 0x1AFF, "Combining Diacritical Marks Extended"},
+	{0x1B00, 0x1B7F, "Balinese"},
+	{0x1B80, 0x1BBF, "Sundanese"},
+	{0x1BC0, 0x1BFF, "Batak"},
+	{0x1C00, 0x1C4F, "Lepcha"},
+	{0x1C50, 0x1C7F, "Ol Chiki"},
+	{0x1C80, 0x1C8F, "Cyrillic Extended-C"},
+	{0x1C90, 0x1CBF, "Georgian Extended"},
+	{0x1CC0, 0x1CCF, "Sundanese Supplement"},
+	{0x1CD0, 0x1CFF, "Vedic Extensions"},
+	{0x1D00, 0x1D7F, "Phonetic Extensions"},
+	{0x1D80, 0x1DBF, "Phonetic Extensions Supplement"},
+	{0x1DC0, 0x1DFF, "Combining Diacritical Marks Supplement"},
+	{0x1E00, 0x1EFF, "Latin Extended Additional"},
+	{0x1F00, 0x1FFF, "Greek Extended"},
+	{0x2000, 0x206F, "General Punctuation"},
+	{0x2070, 0x209F, "Superscripts and Subscripts"},
+	{0x20A0, 0x20CF, "Currency Symbols"},
+	{0x20D0, 0x20FF, "Combining Diacritical Marks for Symbols"},
+	{0x2100, 0x214F, "Letterlike Symbols"},
+	{0x2150, 0x218F, "Number Forms"},
+	{0x2190, 0x21FF, "Arrows"},
+	{0x2200, 0x22FF, "Mathematical Operators"},
+	{0x2300, 0x23FF, "Miscellaneous Technical"},
+	{0x2400, 0x243F, "Control Pictures"},
+	{0x2440, 0x245F, "Optical Character Recognition"},
+	{0x2460, 0x24FF, "Enclosed Alphanumerics"},
+	{0x2500, 0x257F, "Box Drawing"},
+	{0x2580, 0x259F, "Block Elements"},
+	{0x25A0, 0x25FF, "Geometric Shapes"},
+	{0x2600, 0x26FF, "Miscellaneous Symbols"},
+	{0x2700, 0x27BF, "Dingbats"},
+	{0x27C0, 0x27EF, "Miscellaneous Mathematical Symbols-A"},
+	{0x27F0, 0x27FF, "Supplemental Arrows-A"},
+	{0x2800, 0x28FF, "Braille Patterns"},
+	{0x2900, 0x297F, "Supplemental Arrows-B"},
+	{0x2980, 0x29FF, "Miscellaneous Mathematical Symbols-B"},
+	{0x2A00, 0x2AFF, "Supplemental Mathematical Operators"},
+	{0x2B00, 0x2BFF, "Miscellaneous Symbols and Arrows"},
+	{0x2C00, 0x2C5F, "Glagolitic"},
+	{0x2C60, 0x2C7F, "Latin Extended-C"},
+	{0x2C80, 0x2CFF, "Coptic"},
+	{0x2D00, 0x2D2F, "Georgian Supplement"},
+	{0x2D30, 0x2D7F, "Tifinagh"},
+	{0x2D80, 0x2DDF, "Ethiopic Extended"},
+	{0x2DE0, 0x2DFF, "Cyrillic Extended-A"},
+	{0x2E00, 0x2E7F, "Supplemental Punctuation"},
+	{0x2E80, 0x2EFF, "CJK Radicals Supplement"},
+	{0x2F00, 0x2FDF, "Kangxi Radicals"},
+	{0x2FF0, 0x2FFF, "Ideographic Description Characters"},
+	{0x3000, 0x303F, "CJK Symbols and Punctuation"},
+	{0x3040, 0x309F, "Hiragana"},
+	{0x30A0, 0x30FF, "Katakana"},
+	{0x3100, 0x312F, "Bopomofo"},
+	{0x3130, 0x318F, "Hangul Compatibility Jamo"},
+	{0x3190, 0x319F, "Kanbun"},
+	{0x31A0, 0x31BF, "Bopomofo Extended"},
+	{0x31C0, 0x31EF, "CJK Strokes"},
+	{0x31F0, 0x31FF, "Katakana Phonetic Extensions"},
+	{0x3200, 0x32FF, "Enclosed CJK Letters and Months"},
+	{0x3300, 0x33FF, "CJK Compatibility"},
+	{0x3400, 0x4DBF, "CJK Unified Ideographs Extension A"},
+	{0x4DC0, 0x4DFF, "Yijing Hexagram Symbols"},
+	{0x4E00, 0x9FFF, "CJK Unified Ideographs"},
+	{0xA000, 0xA48F, "Yi Syllables"},
+	{0xA490, 0xA4CF, "Yi Radicals"},
+	{0xA4D0, 0xA4FF, "Lisu"},
+	{0xA500, 0xA63F, "Vai"},
+	{0xA640, 0xA69F, "Cyrillic Extended-B"},
+	{0xA6A0, 0xA6FF, "Bamum"},
+	{0xA700, 0xA71F, "Modifier Tone Letters"},
+	{0xA720, 0xA7FF, "Latin Extended-D"},
+	{0xA800, 0xA82F, "Syloti Nagri"},
+	{0xA830, 0xA83F, "Common Indic Number Forms"},
+	{0xA840, 0xA87F, "Phags-pa"},
+	{0xA880, 0xA8DF, "Saurashtra"},
+	{0xA8E0, 0xA8FF, "Devanagari Extended"},
+	{0xA900, 0xA92F, "Kayah Li"},
+	{0xA930, 0xA95F, "Rejang"},
+	{0xA960, 0xA97F, "Hangul Jamo Extended-A"},
+	{0xA980, 0xA9DF, "Javanese"},
+	{0xA9E0, 0xA9FF, "Myanmar Extended-B"},
+	{0xAA00, 0xAA5F, "Cham"},
+	{0xAA60, 0xAA7F, "Myanmar Extended-A"},
+	{0xAA80, 0xAADF, "Tai Viet"},
+	{0xAAE0, 0xAAFF, "Meetei Mayek Extensions"},
+	{0xAB00, 0xAB2F, "Ethiopic Extended-A"},
+	{0xAB30, 0xAB6F, "Latin Extended-E"},
+	{0xAB70, 0xABBF, "Cherokee Supplement"},
+	{0xABC0, 0xABFF, "Meetei Mayek"},
+	{0xAC00, 0xD7AF, "Hangul Syllables"},
+	{0xD7B0, 0xD7FF, "Hangul Jamo Extended-B"},
+	{0xD800, 0xDB7F, "High Surrogates"},
+	{0xDB80, 0xDBFF, "High Private Use Surrogates"},
+	{0xDC00, 0xDFFF, "Low Surrogates"},
+	{0xE000, 0xF8FF, "Private Use Area"},
+	{0xF900, 0xFAFF, "CJK Compatibility Ideographs"},
+	{0xFB00, 0xFB4F, "Alphabetic Presentation Forms"},
+	{0xFB50, 0xFDFF, "Arabic Presentation Forms-A"},
+	{0xFE00, 0xFE0F, "Variation Selectors"},
+	{0xFE10, 0xFE1F, "Vertical Forms"},
+	{0xFE20, 0xFE2F, "Combining Half Marks"},
+	{0xFE30, 0xFE4F, "CJK Compatibility Forms"},
+	{0xFE50, 0xFE6F, "Small Form Variants"},
+	{0xFE70, 0xFEFF, "Arabic Presentation Forms-B"},
+	{0xFF00, 0xFFEF, "Halfwidth and Fullwidth Forms"},
+	{0xFFF0, 0xFFFF, "Specials"},
+	{0x10000, 0x1007F, "Linear B Syllabary"},
+	{0x10080, 0x100FF, "Linear B Ideograms"},
+	{0x10100, 0x1013F, "Aegean Numbers"},
+	{0x10140, 0x1018F, "Ancient Greek Numbers"},
+	{0x10190, 0x101CF, "Ancient Symbols"},
+	{0x101D0, 0x101FF, "Phaistos Disc"},
+	{0x10280, 0x1029F, "Lycian"},
+	{0x102A0, 0x102DF, "Carian"},
+	{0x102E0, 0x102FF, "Coptic Epact Numbers"},
+	{0x10300, 0x1032F, "Old Italic"},
+	{0x10330, 0x1034F, "Gothic"},
+	{0x10350, 0x1037F, "Old Permic"},
+	{0x10380, 0x1039F, "Ugaritic"},
+	{0x103A0, 0x103DF, "Old Persian"},
+	{0x10400, 0x1044F, "Deseret"},
+	{0x10450, 0x1047F, "Shavian"},
+	{0x10480, 0x104AF, "Osmanya"},
+	{0x104B0, 0x104FF, "Osage"},
+	{0x10500, 0x1052F, "Elbasan"},
+	{0x10530, 0x1056F, "Caucasian Albanian"},
+	{0x10570, 0x105BF, "Vithkuqi"},
+	{0x10600, 0x1077F, "Linear A"},
+	{0x10780, 0x107BF, "Latin Extended-F"},
+	{0x10800, 0x1083F, "Cypriot Syllabary"},
+	{0x10840, 0x1085F, "Imperial Aramaic"},
+	{0x10860, 0x1087F, "Palmyrene"},
+	{0x10880, 0x108AF, "Nabataean"},
+	{0x108E0, 0x108FF, "Hatran"},
+	{0x10900, 0x1091F, "Phoenician"},
+	{0x10920, 0x1093F, "Lydian"},
+	{0x10980, 0x1099F, "Meroitic Hieroglyphs"},
+	{0x109A0, 0x109FF, "Meroitic Cursive"},
+	{0x10A00, 0x10A5F, "Kharoshthi"},
+	{0x10A60, 0x10A7F, "Old South Arabian"},
+	{0x10A80, 0x10A9F, "Old North Arabian"},
+	{0x10AC0, 0x10AFF, "Manichaean"},
+	{0x10B00, 0x10B3F, "Avestan"},
+	{0x10B40, 0x10B5F, "Inscriptional Parthian"},
+	{0x10B60, 0x10B7F, "Inscriptional Pahlavi"},
+	{0x10B80, 0x10BAF, "Psalter Pahlavi"},
+	{0x10C00, 0x10C4F, "Old Turkic"},
+	{0x10C80, 0x10CFF, "Old Hungarian"},
+	{0x10D00, 0x10D3F, "Hanifi Rohingya"},
+	{0x10E60, 0x10E7F, "Rumi Numeral Symbols"},
+	{0x10E80, 0x10EBF, "Yezidi"},
+	{0x10F00, 0x10F2F, "Old Sogdian"},
+	{0x10F30, 0x10F6F, "Sogdian"},
+	{0x10F70, 0x10FAF, "Old Uyghur"},
+	{0x10FB0, 0x10FDF, "Chorasmian"},
+	{0x10FE0, 0x10FFF, "Elymaic"},
+	{0x11000, 0x1107F, "Brahmi"},
+	{0x11080, 0x110CF, "Kaithi"},
+	{0x110D0, 0x110FF, "Sora Sompeng"},
+	{0x11100, 0x1114F, "Chakma"},
+	{0x11150, 0x1117F, "Mahajani"},
+	{0x11180, 0x111DF, "Sharada"},
+	{0x111E0, 0x111FF, "Sinhala Archaic Numbers"},
+	{0x11200, 0x1124F, "Khojki"},
+	{0x11280, 0x112AF, "Multani"},
+	{0x112B0, 0x112FF, "Khudawadi"},
+	{0x11300, 0x1137F, "Grantha"},
+	{0x11400, 0x1147F, "Newa"},
+	{0x11480, 0x114DF, "Tirhuta"},
+	{0x11580, 0x115FF, "Siddham"},
+	{0x11600, 0x1165F, "Modi"},
+	{0x11660, 0x1167F, "Mongolian Supplement"},
+	{0x11680, 0x116CF, "Takri"},
+	{0x11700, 0x1174F, "Ahom"},
+	{0x11800, 0x1184F, "Dogra"},
+	{0x118A0, 0x118FF, "Warang Citi"},
+	{0x11900, 0x1195F, "Dives Akuru"},
+	{0x119A0, 0x119FF, "Nandinagari"},
+	{0x11A00, 0x11A4F, "Zanabazar Square"},
+	{0x11A50, 0x11AAF, "Soyombo"},
+	{0x11AB0, 0x11ABF, "Unified Canadian Aboriginal Syllabics Extended-A"},
+	{0x11AC0, 0x11AFF, "Pau Cin Hau"},
+	{0x11C00, 0x11C6F, "Bhaiksuki"},
+	{0x11C70, 0x11CBF, "Marchen"},
+	{0x11D00, 0x11D5F, "Masaram Gondi"},
+	{0x11D60, 0x11DAF, "Gunjala Gondi"},
+	{0x11EE0, 0x11EFF, "Makasar"},
+	{0x11FB0, 0x11FBF, "Lisu Supplement"},
+	{0x11FC0, 0x11FFF, "Tamil Supplement"},
+	{0x12000, 0x123FF, "Cuneiform"},
+	{0x12400, 0x1247F, "Cuneiform Numbers and Punctuation"},
+	{0x12480, 0x1254F, "Early Dynastic Cuneiform"},
+	{0x12F90, 0x12FFF, "Cypro-Minoan"},
+	{0x13000, 0x1342F, "Egyptian Hieroglyphs"},
+	{0x13430, 0x1343F, "Egyptian Hieroglyph Format Controls"},
+	{0x14400, 0x1467F, "Anatolian Hieroglyphs"},
+	{0x16800, 0x16A3F, "Bamum Supplement"},
+	{0x16A40, 0x16A6F, "Mro"},
+	{0x16A70, 0x16ACF, "Tangsa"},
+	{0x16AD0, 0x16AFF, "Bassa Vah"},
+	{0x16B00, 0x16B8F, "Pahawh Hmong"},
+	{0x16E40, 0x16E9F, "Medefaidrin"},
+	{0x16F00, 0x16F9F, "Miao"},
+	{0x16FE0, 0x16FFF, "Ideographic Symbols and Punctuation"},
+	{0x17000, 0x187FF, "Tangut"},
+	{0x18800, 0x18AFF, "Tangut Components"},
+	{0x18B00, 0x18CFF, "Khitan Small Script"},
+	{0x18D00, 0x18D7F, "Tangut Supplement"},
+	{0x1AFF0, 0x1AFFF, "Kana Extended-B"},
+	{0x1B000, 0x1B0FF, "Kana Supplement"},
+	{0x1B100, 0x1B12F, "Kana Extended-A"},
+	{0x1B130, 0x1B16F, "Small Kana Extension"},
+	{0x1B170, 0x1B2FF, "Nushu"},
+	{0x1BC00, 0x1BC9F, "Duployan"},
+	{0x1BCA0, 0x1BCAF, "Shorthand Format Controls"},
+	{0x1CF00, 0x1CFCF, "Znamenny Musical Notation"},
+	{0x1D000, 0x1D0FF, "Byzantine Musical Symbols"},
+	{0x1D100, 0x1D1FF, "Musical Symbols"},
+	{0x1D200, 0x1D24F, "Ancient Greek Musical Notation"},
+	{0x1D2E0, 0x1D2FF, "Mayan Numerals"},
+	{0x1D300, 0x1D35F, "Tai Xuan Jing Symbols"},
+	{0x1D360, 0x1D37F, "Counting Rod Numerals"},
+	{0x1D400, 0x1D7FF, "Mathematical Alphanumeric Symbols"},
+	{0x1D800, 0x1DAAF, "Sutton SignWriting"},
+	{0x1DF00, 0x1DFFF, "Latin Extended-G"},
+	{0x1E000, 0x1E02F, "Glagolitic Supplement"},
+	{0x1E100, 0x1E14F, "Nyiakeng Puachue Hmong"},
+	{0x1E290, 0x1E2BF, "Toto"},
+	{0x1E2C0, 0x1E2FF, "Wancho"},
+	{0x1E7E0, 0x1E7FF, "Ethiopic Extended-B"},
+	{0x1E800, 0x1E8DF, "Mende Kikakui"},
+	{0x1E900, 0x1E95F, "Adlam"},
+	{0x1EC70, 0x1ECBF, "Indic Siyaq Numbers"},
+	{0x1ED00, 0x1ED4F, "Ottoman Siyaq Numbers"},
+	{0x1EE00, 0x1EEFF, "Arabic Mathematical Alphabetic Symbols"},
+	{0x1F000, 0x1F02F, "Mahjong Tiles"},
+	{0x1F030, 0x1F09F, "Domino Tiles"},
+	{0x1F0A0, 0x1F0FF, "Playing Cards"},
+	{0x1F100, 0x1F1FF, "Enclosed Alphanumeric Supplement"},
+	{0x1F200, 0x1F2FF, "Enclosed Ideographic Supplement"},
+	{0x1F300, 0x1F5FF, "Miscellaneous Symbols and Pictographs"},
+	{0x1F600, 0x1F64F, "Emoticons"},
+	{0x1F650, 0x1F67F, "Ornamental Dingbats"},
+	{0x1F680, 0x1F6FF, "Transport and Map Symbols"},
+	{0x1F700, 0x1F77F, "Alchemical Symbols"},
+	{0x1F780, 0x1F7FF, "Geometric Shapes Extended"},
+	{0x1F800, 0x1F8FF, "Supplemental Arrows-C"},
+	{0x1F900, 0x1F9FF, "Supplemental Symbols and Pictographs"},
+	{0x1FA00, 0x1FA6F, "Chess Symbols"},
+	{0x1FA70, 0x1FAFF, "Symbols and Pictographs Extended-A"},
+	{0x1FB00, 0x1FBFF, "Symbols for Legacy Computing"},
+	{0x20000, 0x2A6DF, "CJK Unified Ideographs Extension B"},
+	{0x2A700, 0x2B73F, "CJK Unified Ideographs Extension C"},
+	{0x2B740, 0x2B81F, "CJK Unified Ideographs Extension D"},
+	{0x2B820, 0x2CEAF, "CJK Unified Ideographs Extension E"},
+	{0x2CEB0, 0x2EBEF, "CJK Unified Ideographs Extension F"},
+	{0x2F800, 0x2FA1F, "CJK Compatibility Ideographs Supplement"},
+	{0x30000, 0x3134F, "CJK Unified Ideographs Extension G"},
+	{0xE0000, 0xE007F, "Tags"},
+	{0xE0100, 0xE01EF, "Variation Selectors Supplement"},
+	{0xF0000, 0xFFFFF, "Supplementary Private Use Area-A"},
+	{0x100000, 0x10FFFF, "Supplementary Private Use Area-B"},
+}
 
-	headerList := []string{} // store seen headers for later
-	capturingHeader := false // are we currently capturing a header?
-	headerBuf := []string{}
+// character data read from unicodedata.txt: names, canonical combining
+// classes, canonical decompositions and the pairs NFC composes
+var unicodeNames map[rune]string
+var unicodeCCC map[rune]int
+var unicodeDecomp map[rune][]rune
+var unicodeComp map[[2]rune]rune
+
+// allowed character profiles that can be named with -u
+var charProfiles = map[string][]runeRange{
+	"ascii":        {{0x20, 0x7E}},
+	"latin1":       {{0x20, 0x7E}, {0xA0, 0xFF}},
+	"latin1-curly": {{0x20, 0x7E}, {0xA0, 0xFF}, {0x2014, 0x2014}, {0x2018, 0x2019}, {0x201C, 0x201D}},
+}
 
-	// helper: normalize block into one line
-	normalizeBlock := func(lines []string) string {
-		parts := []string{}
-		for _, l := range lines {
-			t := strings.TrimSpace(l)
-			if t != "" {
-				parts = append(parts, t)
+// characters allowed in the book (-u). empty if any character is allowed
+var allowedChars []runeRange
+
+// read unicodedata.txt. one "XXXX;NAME" per line, followed by
+// ";CCC;DDDD EEEE" for a character with a combining class or canonical
+// decomposition, and ";x" if NFC does not compose it again. lines
+// starting with "#" are comments
+func readUnicodeData(infile string) {
+	unicodeNames = make(map[rune]string)
+	unicodeCCC = make(map[rune]int)
+	unicodeDecomp = make(map[rune][]rune)
+	unicodeComp = make(map[[2]rune]rune)
+	file, err := os.Open(infile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		t := strings.Split(line, ";")
+		if len(t) < 2 {
+			continue
+		}
+		cp, err := strconv.ParseUint(t[0], 16, 32)
+		if err != nil {
+			continue
+		}
+		c := rune(cp)
+		if t[1] != "" {
+			unicodeNames[c] = t[1]
+		}
+		if len(t) < 4 {
+			continue
+		}
+		if ccc, _ := strconv.Atoi(t[2]); ccc != 0 {
+			unicodeCCC[c] = ccc
+		}
+		d := []rune{}
+		for _, f := range strings.Fields(t[3]) {
+			if dp, err := strconv.ParseUint(f, 16, 32); err == nil {
+				d = append(d, rune(dp))
 			}
 		}
-		if len(parts) == 0 {
-			return ""
+		if len(d) > 0 {
+			unicodeDecomp[c] = d
 		}
-		return " " + strings.Join(parts, " ")
+		if len(d) == 2 && (len(t) < 5 || t[4] != "x") {
+			unicodeComp[[2]rune{d[0], d[1]}] = c
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
 	}
+}
 
-	for n, line := range wb {
-		if len(strings.TrimSpace(line)) == 0 { // all whitespace
-			consec++
+// read an allowed character profile: a profile name from charProfiles or
+// a file. each line of the file is a profile name, a code point "U+2014",
+// a range "U+2018..U+201D" or the characters themselves. lines starting
+// with "#" are comments
+var reProfileRange *regexp.Regexp = regexp.MustCompile(`^U\+([0-9A-Fa-f]{4,6})(\.\.U\+([0-9A-Fa-f]{4,6}))?$`)
 
-			// 4 blank lines were just seen; capture the header text
-			if capturingHeader {
-				if len(headerBuf) > 0 {
-					headerList = append(headerList, normalizeBlock(headerBuf))
-					headerBuf = []string{}
-				}
-				capturingHeader = false
-			}
+func readCharProfile(spec string) []runeRange {
+	if rr, ok := charProfiles[spec]; ok {
+		return rr
+	}
+	rr := []runeRange{}
+	for n, line := range readText(spec) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
 			continue
 		}
-		// a non-blank line
-		// if we hit a non-blank line after having seen four or more
-		// consecutive blank lines, start a new line of output
-		if consec >= 4 {
-			// flush any existing line
-			s = re1.ReplaceAllString(s, "1..1")
-			s = re2a.ReplaceAllString(s, "☰3☷")
-			s = re2b.ReplaceAllString(s, "☰5☷")
-			s = re2c.ReplaceAllString(s, "☰22☷")
-			s = re2d.ReplaceAllString(s, "☰44☷")
-			s = fmt.Sprintf("%6d %s", lastn, s)
-			rs = append(rs, s)
-			s = fmt.Sprintf("%d", consec)
-			lastn = n
-
-			// after 4 blanks we are starting a header; capture it
-			capturingHeader = true
-			headerBuf = []string{line}
-		} else {
-			// we have fewer than four but at least one to report
-			if consec > 0 {
-				s = fmt.Sprintf("%s%d", s, consec)
+		if t, ok := charProfiles[line]; ok {
+			rr = append(rr, t...)
+			continue
+		}
+		if t := reProfileRange.FindStringSubmatch(line); t != nil {
+			lo, _ := strconv.ParseUint(t[1], 16, 32)
+			hi := lo
+			if t[3] != "" {
+				hi, _ = strconv.ParseUint(t[3], 16, 32)
 			}
+			rr = append(rr, runeRange{rune(lo), rune(hi)})
+			continue
+		}
+		if strings.HasPrefix(line, "U+") {
+			log.Fatalf("%s line %d: bad code point or range: %s", spec, n+1, line)
+		}
+		for _, c := range line {
+			rr = append(rr, runeRange{c, c})
+		}
+	}
+	if len(rr) == 0 {
+		log.Fatalf("no allowed characters in profile %s", spec)
+	}
+	return rr
+}
 
-			if capturingHeader {
-				headerBuf = append(headerBuf, line)
-			}
+// character is in the allowed profile
+func allowedChar(c rune) bool {
+	for _, rr := range allowedChars {
+		if c >= rr.lo && c <= rr.hi {
+			return true
 		}
-		consec = 0 // a non-blank line seen; start count over
 	}
-	s = re1.ReplaceAllString(s, "1..1")
-	s = fmt.Sprintf("%6d %s", lastn, s)
-	rs = append(rs, s) // last line in buffer
+	return false
+}
 
-	// flush last captured header if still active
-	if capturingHeader && len(headerBuf) > 0 {
-		headerList = append(headerList, normalizeBlock(headerBuf))
+// Unicode block a character is in
+func blockOf(c rune) string {
+	i := sort.Search(len(unicodeBlocks), func(i int) bool { return unicodeBlocks[i].hi >= c })
+	if i < len(unicodeBlocks) && unicodeBlocks[i].lo <= c {
+		return unicodeBlocks[i].name
 	}
+	return "No_Block"
+}
 
-	// always dim
-	rs = append(rs, "")
-	rs = append(rs, "----- main text headers -------------------------------------------------------")
-	for _, b := range headerList {
-		// Convert string to rune slice (to handle UTF-8 safely)
-		runes := []rune(b)
-		if len(runes) > 80 {
-			runes = append(runes[:75], []rune("[...]")...)
+// the two letter Unicode general categories, sorted
+var generalCategories = func() []string {
+	cats := []string{}
+	for name := range unicode.Categories {
+		if len(name) == 2 && name != "LC" { // LC is Lu, Ll and Lt together
+			cats = append(cats, name)
 		}
-		b = string(runes)
-		rs = append(rs, b)
 	}
-	rs = append(rs, "")
-	// rs[0] = "☲" + rs[0]  // style dim
-	// rs[len(rs)-1] += "☷" // close style
-	return rs
+	sort.Strings(cats)
+	return cats
+}()
+
+// two letter Unicode general category of a character
+func categoryOf(c rune) string {
+	for _, name := range generalCategories {
+		if unicode.Is(unicode.Categories[name], c) {
+			return name
+		}
+	}
+	return "Cn" // unassigned
 }
 
-// book-level checks
-func tcBookLevel(wb []string) []string {
+// Unicode name of a character. names made by rule are built here
+func runeName(c rune) string {
+	if name, ok := unicodeNames[c]; ok {
+		return name
+	}
+	if c >= 0xAC00 && c <= 0xD7A3 { // Hangul syllable from its jamo
+		l := []string{"G", "GG", "N", "D", "DD", "R", "M", "B", "BB", "S", "SS", "", "J", "JJ", "C", "K", "T", "P", "H"}
+		v := []string{"A", "AE", "YA", "YAE", "EO", "E", "YEO", "YE", "O", "WA", "WAE", "OE", "YO", "U", "WEO", "WE", "WI", "YU", "EU", "YI", "I"}
+		t := []string{"", "G", "GG", "GS", "N", "NJ", "NH", "D", "L", "LG", "LM", "LB", "LS", "LT", "LP", "LH", "M", "B", "BS", "S", "SS", "NG", "J", "C", "K", "T", "P", "H"}
+		n := int(c - 0xAC00)
+		return "HANGUL SYLLABLE " + l[n/(21*28)] + v[(n%(21*28))/28] + t[n%28]
+	}
+	block := blockOf(c)
+	switch {
+	case strings.HasPrefix(block, "CJK Compatibility Ideographs"):
+		return fmt.Sprintf("CJK COMPATIBILITY IDEOGRAPH-%04X", c)
+	case strings.HasPrefix(block, "Tangut"):
+		return fmt.Sprintf("TANGUT IDEOGRAPH-%04X", c)
+	case block == "Nushu":
+		return fmt.Sprintf("NUSHU CHARACTER-%04X", c)
+	case block == "Khitan Small Script":
+		return fmt.Sprintf("KHITAN SMALL SCRIPT CHARACTER-%04X", c)
+	case unicode.Is(unicode.Ideographic, c):
+		return fmt.Sprintf("CJK UNIFIED IDEOGRAPH-%04X", c)
+	case unicode.IsControl(c):
+		return "<control>"
+	}
+	return "<unknown>"
+}
+
+// character inventory
+// every character in the book with its code point, count, general
+// category, block and name. with an allowed character profile (-u), any
+// character outside it is reported however often it occurs
+func tcCharInventory(wb []string) []string {
 	rs := []string{}
-	rs = append(rs, "----- book level checks -----------------------------------------------")
+	rs = append(rs, "----- character inventory -----------------------------------------------------")
 	rs = append(rs, "")
-	count := 0
-	needsep := false
-	// check: straight and curly quotes mixed
-	if m['\''] > 0 && (m['‘'] > 0 || m['’'] > 0) {
-		rs = append(rs, "  both straight and curly ◨single◧ quotes found in text")
-		count++
-		needsep = true
+
+	counts := map[rune]int{}
+	for _, line := range wb {
+		for _, c := range line {
+			counts[c]++
+		}
+	}
+	var ss []kv
+	for k, v := range counts {
+		ss = append(ss, kv{k, v})
 	}
+	sort.Slice(ss, func(i, j int) bool {
+		return ss[i].Key < ss[j].Key
+	})
 
-	if m['"'] > 0 && (m['“'] > 0 || m['”'] > 0) {
-		rs = append(rs, "  both straight and curly ◨double◧ quotes found in text")
-		count++
-		needsep = true
+	if len(allowedChars) > 0 {
+		rs = append(rs, fmt.Sprintf("  allowed characters: %s", p.Profile))
+		rs = append(rs, "")
+	}
+	notallowed := []rune{}
+	for _, kv := range ss {
+		glyph := " "
+		if unicode.IsGraphic(kv.Key) && !unicode.IsSpace(kv.Key) {
+			glyph = string(kv.Key)
+			if unicode.Is(unicode.Mn, kv.Key) {
+				glyph = "◌" + glyph // combining mark on a dotted circle
+			}
+			glyph = strings.Replace(glyph, "<", "&lt;", -1)
+			glyph = strings.Replace(glyph, ">", "&gt;", -1)
+		}
+		line := fmt.Sprintf("  U+%04X %s %7d  %s  %-24s %s", kv.Key, glyph, kv.Value,
+			categoryOf(kv.Key), blockOf(kv.Key), runeName(kv.Key))
+		if len(allowedChars) > 0 && !allowedChar(kv.Key) {
+			notallowed = append(notallowed, kv.Key)
+			line += "  ☰not allowed☷"
+		}
+		rs = append(rs, line)
 	}
+	rs = append(rs, "")
 
-	if needsep {
+	// where the characters outside the profile are
+	for _, c := range notallowed {
+		rs = append(rs, fmt.Sprintf("U+%04X %s", c, runeName(c)))
+		reportcount := 0
+		for n, line := range wb {
+			if strings.ContainsRune(line, c) {
+				if p.Verbose || reportcount < 5 {
+					line = strings.Replace(line, string(c), "☰"+string(c)+"☷", -1)
+					rs = append(rs, fmt.Sprintf("  %5d: %s", n+1, pt(line))) // 1=based
+				}
+				reportcount++
+			}
+		}
+		if !p.Verbose && reportcount > 5 {
+			rs = append(rs, fmt.Sprintf("         ... %d more", reportcount-5))
+		}
 		rs = append(rs, "")
-		needsep = false
 	}
 
-	// ----- check "a. m." and "a.m." (and similar) mixed -----
-	cam, cams, cpm, cpms := 0, 0, 0, 0
-	re01a := regexp.MustCompile(`(?i)a\.m\.`)
-	re02a := regexp.MustCompile(`(?i)a\.\s+m\.`)
-	re03a := regexp.MustCompile(`(?i)p\.m\.`)
-	re04a := regexp.MustCompile(`(?i)p\.\s+m\.`)
-	for _, line := range wb {
-		cam += len(re01a.FindAllString(line, -1))
-		cams += len(re02a.FindAllString(line, -1))
-		cpm += len(re03a.FindAllString(line, -1))
-		cpms += len(re04a.FindAllString(line, -1))
+	if len(notallowed) == 0 {
+		rs[0] = "☲" + rs[0] // style dim
+	} else {
+		rs[0] = "☳" + rs[0] // style black
 	}
-	if cam > 0 && cams > 0 {
-		rs = append(rs, "  both \"a.m.\" and \"a. m.\" found in text")
-		count++
-		needsep = true
+	rs[len(rs)-1] += "☷" // close style
+	tcec += len(notallowed)
+	return rs
+}
+
+// Hangul syllables are composed and decomposed by rule
+const (
+	hangulS = 0xAC00
+	hangulL = 0x1100
+	hangulV = 0x1161
+	hangulT = 0x11A7
+	hangulN = 21 * 28 // syllables for each leading consonant
+)
+
+// full canonical decomposition of a character
+func decomposeRune(c rune, out []rune) []rune {
+	if c >= hangulS && c < hangulS+19*hangulN {
+		n := c - hangulS
+		out = append(out, hangulL+n/hangulN, hangulV+(n%hangulN)/28)
+		if n%28 != 0 {
+			out = append(out, hangulT+n%28)
+		}
+		return out
 	}
-	if cpm > 0 && cpms > 0 {
-		rs = append(rs, "  both \"p.m.\" and \"p. m.\" found in text")
-		count++
-		needsep = true
+	if d, ok := unicodeDecomp[c]; ok {
+		for _, dc := range d {
+			out = decomposeRune(dc, out)
+		}
+		return out
 	}
-	if p.Verbose {
-		rs = append(rs, fmt.Sprintf("%10s: %3d %10s: %3d", "a.m.", cam, "p.m.", cpm))
-		rs = append(rs, fmt.Sprintf("%10s: %3d %10s: %3d", "a. m.", cams, "p. m.", cpms))
-		needsep = true
+	return append(out, c)
+}
+
+// the character NFC composes two characters into, if any
+func composeRunes(a, b rune) (rune, bool) {
+	if a >= hangulL && a < hangulL+19 && b >= hangulV && b < hangulV+21 {
+		return hangulS + ((a-hangulL)*21+b-hangulV)*28, true
 	}
-	if needsep {
-		rs = append(rs, "")
-		needsep = false
+	if a >= hangulS && a < hangulS+19*hangulN && (a-hangulS)%28 == 0 && b > hangulT && b < hangulT+28 {
+		return a + b - hangulT, true
 	}
+	c, ok := unicodeComp[[2]rune{a, b}]
+	return c, ok
+}
 
-	// ----- check to-day and today mixed -----
-	ctoday, ctohday, ctonight, ctohnight, ctomorrow, ctohmorrow := 0, 0, 0, 0, 0, 0
-	re01 := regexp.MustCompile(`(?i)today`)
-	re02 := regexp.MustCompile(`(?i)to-day`)
-	re03 := regexp.MustCompile(`(?i)tonight`)
-	re04 := regexp.MustCompile(`(?i)to-night`)
-	re05 := regexp.MustCompile(`(?i)tomorrow`)
-	re06 := regexp.MustCompile(`(?i)to-morrow`)
-	for _, line := range wb {
-		ctoday += len(re01.FindAllString(line, -1))
-		ctohday += len(re02.FindAllString(line, -1))
-		ctonight += len(re03.FindAllString(line, -1))
-		ctohnight += len(re04.FindAllString(line, -1))
-		ctomorrow += len(re05.FindAllString(line, -1))
-		ctohmorrow += len(re06.FindAllString(line, -1))
-	}
-	if ctoday > 0 && ctohday > 0 {
-		// rs = append(rs, "  ☱both \"today\" and \"to-day\" found in text☷")
-		rs = append(rs, "  both \"today\" and \"to-day\" found in text")
-		count++
-		needsep = true
+// Unicode Normalization Form C of a string
+func nfc(s string) string {
+	// decompose, then put combining marks in canonical order
+	d := []rune{}
+	for _, c := range s {
+		d = decomposeRune(c, d)
 	}
-	if ctonight > 0 && ctohnight > 0 {
-		rs = append(rs, "  both \"tonight\" and \"to-night\" found in text")
-		count++
-		needsep = true
+	for i := 1; i < len(d); i++ {
+		for j := i; j > 0 && unicodeCCC[d[j]] != 0 && unicodeCCC[d[j-1]] > unicodeCCC[d[j]]; j-- {
+			d[j], d[j-1] = d[j-1], d[j]
+		}
 	}
-	if ctomorrow > 0 && ctohmorrow > 0 {
-		rs = append(rs, "  both \"tomorrow\" and \"to-morrow\" found in text")
-		count++
-		needsep = true
+	if len(d) == 0 {
+		return s
 	}
-	if p.Verbose {
-		rs = append(rs, fmt.Sprintf("%10s: %3d %10s: %3d %10s: %3d ", "today", ctoday, "tonight", ctonight,
-			"tomorrow", ctomorrow))
-		rs = append(rs, fmt.Sprintf("%10s: %3d %10s: %3d %10s: %3d ", "to-day", ctohday, "to-night", ctohnight,
-			"to-morrow", ctohmorrow))
-		needsep = true
+
+	// compose each character with the last starter when nothing between
+	// them blocks it
+	starter := 0
+	lastClass := unicodeCCC[d[0]]
+	if lastClass != 0 {
+		lastClass = 256 // a combining mark with no starter
 	}
-	if needsep {
+	out := 1
+	for i := 1; i < len(d); i++ {
+		c := d[i]
+		class := unicodeCCC[c]
+		if comp, ok := composeRunes(d[starter], c); ok && (lastClass < class || lastClass == 0) {
+			d[starter] = comp
+			continue
+		}
+		if class == 0 {
+			starter = out
+		}
+		lastClass = class
+		d[out] = c
+		out++
+	}
+	return string(d[:out])
+}
+
+// characters that cannot be seen or that change how text is handled:
+// format and control characters and spaces other than the plain space
+func invisibleChar(c rune) bool {
+	return c != ' ' && (unicode.In(c, unicode.Cf, unicode.Cc, unicode.Zs, unicode.Zl, unicode.Zp))
+}
+
+// normalization check
+// reports sequences that are not in NFC, such as "e" followed by a
+// combining acute accent, and invisible or format characters with their
+// line and column
+func tcNormalization(wb []string) []string {
+	rs := []string{}
+	rs = append(rs, "----- normalization check -----------------------------------------------------")
+	rs = append(rs, "")
+
+	if unicodeCCC == nil {
+		rs = append(rs, "  no unicodedata.txt found: normalization not checked.")
+		rs[0] = "☲" + rs[0] // style dim
 		rs = append(rs, "")
-		needsep = false
+		rs[len(rs)-1] += "☷" // close style
+		return rs
 	}
 
-	// ----- check compass directions mixed -----
-	cnortheast, cnorthheast, cnorthwest, cnorthhwest := 0, 0, 0, 0
-	csoutheast, csouthheast, csouthwest, csouthhwest := 0, 0, 0, 0
+	count := 0
+	// a sequence is a starter and the combining marks after it. a
+	// sequence that composes with the one before it joins it
+	for n, line := range wb {
+		if nfc(line) == line {
+			continue
+		}
+		rl := []rune(line)
+		seqs := [][2]int{} // rune start, end of each sequence
+		for i := 0; i < len(rl); i++ {
+			if i == 0 || unicodeCCC[rl[i]] == 0 {
+				seqs = append(seqs, [2]int{i, i + 1})
+				if k := len(seqs) - 1; k > 0 {
+					prev, cur := seqs[k-1], seqs[k]
+					if nfc(string(rl[prev[0]:cur[1]])) != nfc(string(rl[prev[0]:prev[1]]))+nfc(string(rl[cur[0]:cur[1]])) {
+						seqs = seqs[:k]
+						seqs[k-1][1] = i + 1
+					}
+				}
+			} else {
+				seqs[len(seqs)-1][1] = i + 1
+			}
+		}
+		for _, sq := range seqs {
+			seq := string(rl[sq[0]:sq[1]])
+			norm := nfc(seq)
+			if norm == seq {
+				continue
+			}
+			if count == 0 {
+				rs = append(rs, "not in NFC:")
+			}
+			count++
+			if count <= 5 || p.Verbose {
+				from, to := []string{}, []string{}
+				for _, c := range seq {
+					from = append(from, fmt.Sprintf("U+%04X", c))
+				}
+				for _, c := range norm {
+					to = append(to, fmt.Sprintf("U+%04X", c))
+				}
+				rs = append(rs, fmt.Sprintf("  %5d:%-3d %s → %s (%s)", n+1, sq[0]+1,
+					strings.Join(from, " "), strings.Join(to, " "), norm))
+			}
+		}
+	}
+	if !p.Verbose && count > 5 {
+		rs = append(rs, fmt.Sprintf("         ... %d more", count-5))
+	}
+	if count > 0 {
+		rs = append(rs, "")
+	}
+
+	// invisible and format characters, grouped by character
+	where := make(map[rune][]string)
+	seen := make(map[rune]int)
+	chars := []rune{}
+	for n, line := range wb {
+		for col, c := range []rune(line) {
+			if !invisibleChar(c) {
+				continue
+			}
+			if seen[c] == 0 {
+				chars = append(chars, c)
+			}
+			if seen[c] < 5 || p.Verbose {
+				shown := strings.Replace(line, string(c), fmt.Sprintf("☰[U+%04X]☷", c), -1)
+				where[c] = append(where[c], fmt.Sprintf("  %5d:%-3d %s", n+1, col+1, pt(shown)))
+			}
+			seen[c]++
+			count++
+		}
+	}
+	sort.Slice(chars, func(i, j int) bool { return chars[i] < chars[j] })
+	for _, c := range chars {
+		rs = append(rs, fmt.Sprintf("U+%04X %s", c, runeName(c)))
+		rs = append(rs, where[c]...)
+		if !p.Verbose && seen[c] > 5 {
+			rs = append(rs, fmt.Sprintf("         ... %d more", seen[c]-5))
+		}
+		rs = append(rs, "")
+	}
 
-	re11 := regexp.MustCompile(`(?i)northeast`)
-	re12 := regexp.MustCompile(`(?i)north-east`)
-	re13 := regexp.MustCompile(`(?i)northwest`)
-	re14 := regexp.MustCompile(`(?i)north-west`)
-	re15 := regexp.MustCompile(`(?i)southeast`)
-	re16 := regexp.MustCompile(`(?i)south-east`)
-	re17 := regexp.MustCompile(`(?i)southwest`)
-	re18 := regexp.MustCompile(`(?i)south-west`)
+	if count == 0 {
+		rs = append(rs, "  no normalization or invisible character problems found.")
+		rs[0] = "☲" + rs[0] // style dim
+		rs = append(rs, "")
+	} else {
+		rs[0] = "☳" + rs[0] // style black
+	}
+	rs[len(rs)-1] += "☷" // close style
+	tcec += count
+	return rs
+}
+
+// letters of other scripts that look like a Latin letter, mapped to that
+// letter. replaced by the entries of confusables.txt if it is present;
+// this built in table is the Cyrillic and Greek part of it
+var confusables = map[rune]rune{
+	0x0391: 'A', // GREEK CAPITAL LETTER ALPHA
+	0x0392: 'B', // GREEK CAPITAL LETTER BETA
+	0x0395: 'E', // GREEK CAPITAL LETTER EPSILON
+	0x0396: 'Z', // GREEK CAPITAL LETTER ZETA
+	0x0397: 'H', // GREEK CAPITAL LETTER ETA
+	0x0399: 'I', // GREEK CAPITAL LETTER IOTA
+	0x039A: 'K', // GREEK CAPITAL LETTER KAPPA
+	0x039C: 'M', // GREEK CAPITAL LETTER MU
+	0x039D: 'N', // GREEK CAPITAL LETTER NU
+	0x039F: 'O', // GREEK CAPITAL LETTER OMICRON
+	0x03A1: 'P', // GREEK CAPITAL LETTER RHO
+	0x03A4: 'T', // GREEK CAPITAL LETTER TAU
+	0x03A5: 'Y', // GREEK CAPITAL LETTER UPSILON
+	0x03A7: 'X', // GREEK CAPITAL LETTER CHI
+	0x03B1: 'a', // GREEK SMALL LETTER ALPHA
+	0x03B3: 'y', // GREEK SMALL LETTER GAMMA
+	0x03B9: 'i', // GREEK SMALL LETTER IOTA
+	0x03BD: 'v', // GREEK SMALL LETTER NU
+	0x03BF: 'o', // GREEK SMALL LETTER OMICRON
+	0x03C1: 'p', // GREEK SMALL LETTER RHO
+	0x03C5: 'u', // GREEK SMALL LETTER UPSILON
+	0x03F2: 'c', // GREEK LUNATE SIGMA SYMBOL
+	0x03F3: 'j', // GREEK LETTER YOT
+	0x03F9: 'C', // GREEK CAPITAL LUNATE SIGMA SYMBOL
+	0x0405: 'S', // CYRILLIC CAPITAL LETTER DZE
+	0x0406: 'I', // CYRILLIC CAPITAL LETTER BYELORUSSIAN-UKRAINIAN I
+	0x0408: 'J', // CYRILLIC CAPITAL LETTER JE
+	0x0410: 'A', // CYRILLIC CAPITAL LETTER A
+	0x0412: 'B', // CYRILLIC CAPITAL LETTER VE
+	0x0415: 'E', // CYRILLIC CAPITAL LETTER IE
+	0x041A: 'K', // CYRILLIC CAPITAL LETTER KA
+	0x041C: 'M', // CYRILLIC CAPITAL LETTER EM
+	0x041D: 'H', // CYRILLIC CAPITAL LETTER EN
+	0x041E: 'O', // CYRILLIC CAPITAL LETTER O
+	0x0420: 'P', // CYRILLIC CAPITAL LETTER ER
+	0x0421: 'C', // CYRILLIC CAPITAL LETTER ES
+	0x0422: 'T', // CYRILLIC CAPITAL LETTER TE
+	0x0425: 'X', // CYRILLIC CAPITAL LETTER HA
+	0x0430: 'a', // CYRILLIC SMALL LETTER A
+	0x0435: 'e', // CYRILLIC SMALL LETTER IE
+	0x043E: 'o', // CYRILLIC SMALL LETTER O
+	0x0440: 'p', // CYRILLIC SMALL LETTER ER
+	0x0441: 'c', // CYRILLIC SMALL LETTER ES
+	0x0443: 'y', // CYRILLIC SMALL LETTER U
+	0x0445: 'x', // CYRILLIC SMALL LETTER HA
+	0x0455: 's', // CYRILLIC SMALL LETTER DZE
+	0x0456: 'i', // CYRILLIC SMALL LETTER BYELORUSSIAN-UKRAINIAN I
+	0x0458: 'j', // CYRILLIC SMALL LETTER JE
+	0x0475: 'v', // CYRILLIC SMALL LETTER IZHITSA
+	0x04AE: 'Y', // CYRILLIC CAPITAL LETTER STRAIGHT U
+	0x04AF: 'y', // CYRILLIC SMALL LETTER STRAIGHT U
+	0x04BB: 'h', // CYRILLIC SMALL LETTER SHHA
+	0x04C0: 'I', // CYRILLIC LETTER PALOCHKA
+	0x04CF: 'l', // CYRILLIC SMALL LETTER PALOCHKA
+	0x0501: 'd', // CYRILLIC SMALL LETTER KOMI DE
+	0x051A: 'Q', // CYRILLIC CAPITAL LETTER QA
+	0x051B: 'q', // CYRILLIC SMALL LETTER QA
+	0x051C: 'W', // CYRILLIC CAPITAL LETTER WE
+	0x051D: 'w', // CYRILLIC SMALL LETTER WE
+}
+
+// read confusables.txt, the Unicode confusables data (UTS #39). lines are
+// "source ; target ; type # comment" with code points in hex. only the
+// letters of other scripts whose target is a single Latin letter are kept
+func readConfusables(infile string) map[rune]rune {
+	cm := make(map[rune]rune)
+	file, err := os.Open(infile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if n == 1 {
+			line = strings.TrimPrefix(line, BOM) // remove BOM if present
+		}
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		t := strings.Split(line, ";")
+		if len(t) < 2 {
+			continue
+		}
+		src, err := strconv.ParseUint(strings.TrimSpace(t[0]), 16, 32)
+		if err != nil {
+			log.Fatalf("%s line %d: bad code point %s", infile, n, strings.TrimSpace(t[0]))
+		}
+		tgt := strings.Fields(t[1])
+		if len(tgt) != 1 {
+			continue // a sequence, such as "rn" for "m"
+		}
+		dst, err := strconv.ParseUint(tgt[0], 16, 32)
+		if err != nil {
+			log.Fatalf("%s line %d: bad code point %s", infile, n, tgt[0])
+		}
+		c, l := rune(src), rune(dst)
+		if sc := scriptOf(c); sc != "" && sc != "Latin" && scriptOf(l) == "Latin" {
+			cm[c] = l
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+	return cm
+}
+
+// script of a letter: Latin, Greek, Cyrillic or another script name.
+// marks and other characters without a script of their own give ""
+var scriptCache = map[rune]string{}
+
+func scriptOf(c rune) string {
+	if sc, ok := scriptCache[c]; ok {
+		return sc
+	}
+	sc := ""
+	if unicode.IsLetter(c) {
+		names := []string{}
+		for name := range unicode.Scripts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if name != "Common" && name != "Inherited" && unicode.Is(unicode.Scripts[name], c) {
+				sc = name
+				break
+			}
+		}
+	}
+	scriptCache[c] = sc
+	return sc
+}
+
+// skeleton of a word: each confusable letter replaced by the Latin
+// letter it looks like
+func skeleton(word string) string {
+	d := []rune{}
+	for _, c := range word {
+		d = decomposeRune(c, d)
+	}
+	for i, c := range d {
+		if t, ok := confusables[c]; ok {
+			d[i] = t
+		}
+	}
+	if unicodeCCC == nil {
+		return string(d)
+	}
+	return nfc(string(d))
+}
+
+// mixed script and confusable check
+// words with letters from more than one script, or words in another
+// script that look exactly like a Latin word in the book. reports the
+// skeleton and, if the book has it, the Latin word the skeleton matches
+func tcHomoglyphs(wb []string) []string {
+	rs := []string{}
+	rs = append(rs, "----- mixed script and confusable check ---------------------------------------")
+	rs = append(rs, "")
+
+	words := []string{}
+	for w := range wordListMapCount {
+		words = append(words, w)
+	}
+	sort.Strings(words)
 
+	count := 0
+	for _, word := range words {
+		scripts := []string{}
+		foreign := []rune{} // confusable letters in the word
+		for _, c := range word {
+			if sc := scriptOf(c); sc != "" && !contains(scripts, sc) {
+				scripts = append(scripts, sc)
+			}
+			if _, ok := confusables[c]; ok {
+				foreign = append(foreign, c)
+			}
+		}
+		skel := skeleton(word)
+		_, latin := wordListMapCount[skel]
+		mixed := len(scripts) > 1
+		lookalike := len(foreign) > 0 && skel != word && latin && !strings.ContainsAny(skel, string(foreign))
+		if !mixed && !lookalike {
+			continue
+		}
+		count++
+
+		s := fmt.Sprintf("%s: %s; skeleton \"%s\"", word, strings.Join(scripts, ", "), skel)
+		if latin && skel != word {
+			s += fmt.Sprintf(" (%d in book)", wordListMapCount[skel])
+		}
+		rs = append(rs, s)
+		shown := map[rune]bool{}
+		for _, c := range word {
+			if sc := scriptOf(c); sc != "" && sc != "Latin" && !shown[c] {
+				rs = append(rs, fmt.Sprintf("    U+%04X %s", c, runeName(c)))
+				shown[c] = true
+			}
+		}
+		re := regexp.MustCompile(`(^|\P{L})(` + regexp.QuoteMeta(word) + `)(\P{L}|$)`)
+		reported := 0
+		for _, ln := range uniqueStrings(strings.Split(wordListMapLines[word], ",")) {
+			n, err := strconv.Atoi(ln)
+			if err != nil {
+				continue
+			}
+			if reported < 5 || p.Verbose {
+				line := re.ReplaceAllString(wb[n-1], `$1☰$2☷$3`)
+				rs = append(rs, fmt.Sprintf("  %5d: %s", n, pt(line)))
+			}
+			reported++
+		}
+		if !p.Verbose && reported > 5 {
+			rs = append(rs, fmt.Sprintf("         ... %d more", reported-5))
+		}
+		rs = append(rs, "")
+	}
+
+	if count == 0 {
+		rs = append(rs, "  no mixed script or confusable words found.")
+		rs[0] = "☲" + rs[0] // style dim
+		rs = append(rs, "")
+	} else {
+		rs[0] = "☳" + rs[0] // style black
+	}
+	rs[len(rs)-1] += "☷" // close style
+	tcec += count
+	return rs
+}
+
+// a [Greek: ] transliteration, which may span lines
+type greekBlock struct {
+	line, col   int    // start of the text after "[Greek:", 0-based
+	eline, ecol int    // the closing bracket, or the end of the paragraph
+	text        string // the transliteration, lines joined with a space
+	closed      bool
+}
+
+// find the [Greek: ] blocks. a block not closed by the end of its
+// paragraph ends there
+func findGreekBlocks(wb []string) []greekBlock {
+	gbs := []greekBlock{}
+	for n := 0; n < len(wb); n++ {
+		from := 0
+		for {
+			i := strings.Index(wb[n][from:], "[Greek:")
+			if i < 0 {
+				break
+			}
+			gb := greekBlock{line: n, col: from + i + len("[Greek:")}
+			l, c := n, gb.col
+			parts := []string{}
+			for {
+				if j := strings.Index(wb[l][c:], "]"); j >= 0 {
+					parts = append(parts, wb[l][c:c+j])
+					gb.eline, gb.ecol, gb.closed = l, c+j, true
+					break
+				}
+				parts = append(parts, wb[l][c:])
+				if l+1 == len(wb) || wb[l+1] == "" {
+					gb.eline, gb.ecol = l, len(wb[l])
+					break
+				}
+				l, c = l+1, 0
+			}
+			gb.text = strings.TrimSpace(strings.Join(parts, " "))
+			gbs = append(gbs, gb)
+			n, from = l, gb.ecol
+		}
+	}
+	return gbs
+}
+
+// copy of the text with the transliteration in each [Greek: ] block
+// masked, so the English word checks do not see it
+func maskGreek(wb []string, gbs []greekBlock) []string {
+	mb := make([]string, len(wb))
+	copy(mb, wb)
+	mask := func(c rune) rune {
+		if c == ' ' {
+			return c
+		}
+		return '◦'
+	}
+	// last block first, so the offsets of earlier ones still hold
+	for i := len(gbs) - 1; i >= 0; i-- {
+		gb := gbs[i]
+		for l := gb.eline; l >= gb.line; l-- {
+			from, to := 0, len(mb[l])
+			if l == gb.line {
+				from = gb.col
+			}
+			if l == gb.eline {
+				to = gb.ecol
+			}
+			mb[l] = mb[l][:from] + strings.Map(mask, mb[l][from:to]) + mb[l][to:]
+		}
+	}
+	return mb
+}
+
+// the enclitics, transliterated without accents or breathings. a word
+// before one of them may take a second, acute accent (anthrôpo/s tis).
+// the forms that are also forms of the article, as tou and hoi, are left
+// out
+var greekEnclitics = map[string]bool{
+	"tis": true, "ti": true, "tinos": true, "tini": true, "tina": true,
+	"tines": true, "tinôn": true, "tisi": true, "tisin": true, "tinas": true,
+	"pou": true, "pote": true, "pôs": true, "pê": true, "poi": true, "pothen": true, "pothi": true,
+	"ge": true, "te": true, "toi": true, "per": true, "nu": true, "nun": true,
+	"mou": true, "moi": true, "me": true, "sou": true, "soi": true, "se": true,
+	"eimi": true, "esti": true, "estin": true, "esmen": true, "este": true, "eisi": true, "eisin": true,
+	"phêmi": true, "phêsi": true, "phêsin": true, "phamen": true, "phate": true, "phasi": true, "phasin": true,
+}
+
+func isGreekEnclitic(word string) bool {
+	bare := strings.Map(func(c rune) rune {
+		if strings.ContainsRune("/\\=()|", c) {
+			return -1
+		}
+		return c
+	}, strings.ToLower(word))
+	return greekEnclitics[bare]
+}
+
+// problems with one word of a transliteration. the scheme has the letters
+// a b g d e ê z th i k l m n x o p r s t u y ph ch ps ô, rough breathing
+// as h or "(" and smooth breathing as ")" after the first vowel, the
+// accents / \ and = after a vowel and | for iota subscript. a word
+// followed by an enclitic may have a second accent if it is acute
+func greekWordProblems(word string, beforeEnclitic bool) []string {
+	const vowels = "aeêēioôōuy"
+	const letters = "abgdeêēzhiklmnxoôōprstuy"
+	probs := []string{}
+	w := []rune(strings.ToLower(word))
+	prev := rune(0)    // the last letter seen
+	onlyVowels := true // no consonant so far
+	accents, breathings := 0, 0
+	lastAccent := rune(0)
+	for i, c := range w {
+		switch {
+		case c == 'c' && i+1 < len(w) && w[i+1] == 'h':
+			onlyVowels = false
+		case unicode.IsLetter(c):
+			if !strings.ContainsRune(letters, c) {
+				probs = append(probs, fmt.Sprintf("letter %c not in the scheme", c))
+			}
+			if !strings.ContainsRune(vowels, c) {
+				onlyVowels = false
+			}
+			prev = c
+		case c == '/' || c == '\\' || c == '=':
+			accents++
+			lastAccent = c
+			if !strings.ContainsRune(vowels, prev) {
+				probs = append(probs, fmt.Sprintf("accent %c not after a vowel", c))
+			} else if c == '=' && (prev == 'e' || prev == 'o') {
+				probs = append(probs, fmt.Sprintf("circumflex on short %c", prev))
+			}
+		case c == '(' || c == ')':
+			breathings++
+			if prev == 'r' && c == '(' && i == 1 {
+				break // initial rho
+			}
+			if !strings.ContainsRune(vowels, prev) || !onlyVowels {
+				probs = append(probs, fmt.Sprintf("breathing %c not on the first vowel", c))
+			}
+		case c == '|':
+			if !strings.ContainsRune("aêô", prev) {
+				probs = append(probs, "iota subscript not after a, ê or ô")
+			}
+		}
+	}
+	if accents > 1 && !(accents == 2 && beforeEnclitic && lastAccent == '/') {
+		probs = append(probs, "more than one accent")
+	}
+	if breathings > 1 {
+		probs = append(probs, "more than one breathing")
+	}
+	return probs
+}
+
+// Greek transliteration check
+// validates each [Greek: ] block against the transliteration scheme
+func tcGreekCheck(wb []string) []string {
+	rs := []string{}
+	rs = append(rs, "----- Greek transliteration check ---------------------------------------------")
+	rs = append(rs, "")
+
+	// punctuation that may surround a word of the transliteration
+	trim := func(c rune) bool {
+		return strings.ContainsRune(".,;:·'’‘“”\"!?-—", c)
+	}
+
+	gbs := findGreekBlocks(wb)
+	count := 0
+	reported := 0
+	for _, gb := range gbs {
+		probs := []string{}
+		bad := []string{}
+		if !gb.closed {
+			probs = append(probs, "not closed before the end of the paragraph")
+		}
+		if gb.text == "" {
+			probs = append(probs, "empty")
+		}
+		words := strings.Fields(gb.text)
+		for i, word := range words {
+			word = strings.TrimFunc(word, trim)
+			beforeEnclitic := i+1 < len(words) && isGreekEnclitic(strings.TrimFunc(words[i+1], trim))
+			for _, prob := range greekWordProblems(word, beforeEnclitic) {
+				probs = append(probs, fmt.Sprintf("%s: %s", word, prob))
+				if !contains(bad, word) {
+					bad = append(bad, word)
+				}
+			}
+		}
+		if len(probs) == 0 {
+			continue
+		}
+		count += len(probs)
+		if reported < 5 || p.Verbose {
+			s := "[Greek: " + gb.text
+			for _, word := range bad {
+				re := regexp.MustCompile(`(^|\s)(` + regexp.QuoteMeta(word) + `)`)
+				s = re.ReplaceAllString(s, `$1☰$2☷`)
+			}
+			if gb.closed {
+				s += "]"
+			}
+			rs = append(rs, fmt.Sprintf("  %5d: %s", gb.line+1, wraptext9(s)))
+			for _, prob := range probs {
+				rs = append(rs, "         "+prob)
+			}
+			rs = append(rs, "")
+		}
+		reported++
+	}
+	if !p.Verbose && reported > 5 {
+		rs = append(rs, fmt.Sprintf("         ... %d more", reported-5))
+		rs = append(rs, "")
+	}
+
+	if count == 0 {
+		if len(gbs) == 0 {
+			rs = append(rs, "  no Greek transliterations found.")
+		} else {
+			rs = append(rs, fmt.Sprintf("  %d Greek transliterations, no problems found.", len(gbs)))
+		}
+		rs[0] = "☲" + rs[0] // style dim
+		rs = append(rs, "")
+	} else {
+		rs[0] = "☳" + rs[0] // style black
+	}
+	rs[len(rs)-1] += "☷" // close style
+	tcec += count
+	return rs
+}
+
+// a header captured by the spacing check, with the line where it starts
+type headerLine struct {
+	lnum int    // 1-based line number
+	text string // header block normalized into one line
+}
+
+// headers seen by tcSpacingCheck, used by later checks
+var bookHeaders []headerLine
+
+// spacing check
+// any spacing is okay until the first 4-space gap. Then
+// expecting 4-1-2 or 4-2 variations only.
+// also captures headers (chapter/h2) in a list to output.
+func tcSpacingCheck(wb []string) []string {
+	rs := []string{}
+	s := ""
+	rs = append(rs, "----- spacing pattern ---------------------------------------------------------")
+	rs = append(rs, "")
+
+	re1 := regexp.MustCompile(`11+1`)
+	re2a := regexp.MustCompile(`3`)
+	re2b := regexp.MustCompile(`5`)
+	re2c := regexp.MustCompile(`22`)
+	re2d := regexp.MustCompile(`44`)
+
+	consec := 0 // consecutive blank lines
+	lastn := 0  // line number of last paragraph start
+
+	headerList := []string{} // store seen headers for later
+	capturingHeader := false // are we currently capturing a header?
+	headerBuf := []string{}
+	headerStart := 0 // line number where the captured header starts
+	bookHeaders = []headerLine{}
+
+	// helper: normalize block into one line
+	normalizeBlock := func(lines []string) string {
+		parts := []string{}
+		for _, l := range lines {
+			t := strings.TrimSpace(l)
+			if t != "" {
+				parts = append(parts, t)
+			}
+		}
+		if len(parts) == 0 {
+			return ""
+		}
+		return " " + strings.Join(parts, " ")
+	}
+
+	for n, line := range wb {
+		if len(strings.TrimSpace(line)) == 0 { // all whitespace
+			consec++
+
+			// 4 blank lines were just seen; capture the header text
+			if capturingHeader {
+				if len(headerBuf) > 0 {
+					headerList = append(headerList, normalizeBlock(headerBuf))
+					bookHeaders = append(bookHeaders, headerLine{headerStart, normalizeBlock(headerBuf)})
+					headerBuf = []string{}
+				}
+				capturingHeader = false
+			}
+			continue
+		}
+		// a non-blank line
+		// if we hit a non-blank line after having seen four or more
+		// consecutive blank lines, start a new line of output
+		if consec >= 4 {
+			// flush any existing line
+			s = re1.ReplaceAllString(s, "1..1")
+			s = re2a.ReplaceAllString(s, "☰3☷")
+			s = re2b.ReplaceAllString(s, "☰5☷")
+			s = re2c.ReplaceAllString(s, "☰22☷")
+			s = re2d.ReplaceAllString(s, "☰44☷")
+			s = fmt.Sprintf("%6d %s", lastn, s)
+			rs = append(rs, s)
+			s = fmt.Sprintf("%d", consec)
+			lastn = n
+
+			// after 4 blanks we are starting a header; capture it
+			capturingHeader = true
+			headerBuf = []string{line}
+			headerStart = n + 1 // 1-based
+		} else {
+			// we have fewer than four but at least one to report
+			if consec > 0 {
+				s = fmt.Sprintf("%s%d", s, consec)
+			}
+
+			if capturingHeader {
+				headerBuf = append(headerBuf, line)
+			}
+		}
+		consec = 0 // a non-blank line seen; start count over
+	}
+	s = re1.ReplaceAllString(s, "1..1")
+	s = fmt.Sprintf("%6d %s", lastn, s)
+	rs = append(rs, s) // last line in buffer
+
+	// flush last captured header if still active
+	if capturingHeader && len(headerBuf) > 0 {
+		headerList = append(headerList, normalizeBlock(headerBuf))
+		bookHeaders = append(bookHeaders, headerLine{headerStart, normalizeBlock(headerBuf)})
+	}
+
+	// always dim
+	rs = append(rs, "")
+	rs = append(rs, "----- main text headers -------------------------------------------------------")
+	for _, b := range headerList {
+		// Convert string to rune slice (to handle UTF-8 safely)
+		runes := []rune(b)
+		if len(runes) > 80 {
+			runes = append(runes[:75], []rune("[...]")...)
+		}
+		b = string(runes)
+		rs = append(rs, b)
+	}
+	rs = append(rs, "")
+	// rs[0] = "☲" + rs[0]  // style dim
+	// rs[len(rs)-1] += "☷" // close style
+	return rs
+}
+
+// heading number sequence check
+// pulls the number from each header found by tcSpacingCheck, such as
+// "CHAPTER XIV", "BOOK II", "Section 3" or a bare Roman numeral ("IV.",
+// "IV THE RETURN" or "IV—THE RETURN"), and
+// checks that the numbers at each heading level count up by one.
+// a heading level restarting at 1 after a different level heading
+// (BOOK II followed by CHAPTER I) starts a new series.
+func tcHeaderNumbering() []string {
+	rs := []string{}
+	rs = append(rs, "----- heading number sequence check -------------------------------------------")
+	rs = append(rs, "")
+
+	re1 := regexp.MustCompile(`(?i)^(chapter|book|part|section|volume|vol\.|act|scene|canto|letter)\s+([0-9]+|[ivxlcdm]+)(\P{L}|$)`)
+	re2 := regexp.MustCompile(`^([IVXLCDM]+|[ivxlcdm]+)(\.|\s|\p{Pd}|$)`) // bare Roman numeral
+
+	type seqState struct {
+		prev    int    // last number seen at this level
+		prevStr string // as it appeared in the text
+		seen    int    // headers seen at this level
+	}
+	levels := map[string]*seqState{}
+	levelOrder := []string{}
+	lastLevel := ""
+
+	count := 0
+	report := func(h headerLine, msg string) {
+		rs = append(rs, "  "+msg)
+		rs = append(rs, fmt.Sprintf("  %5d: %s", h.lnum, pt(strings.TrimSpace(h.text))))
+		count++
+	}
+
+	for _, h := range bookHeaders {
+		t := strings.TrimSpace(h.text)
+		level, numstr := "", ""
+		if u := re1.FindStringSubmatch(t); u != nil {
+			level = strings.ToLower(u[1])
+			if level == "vol." {
+				level = "volume"
+			}
+			numstr = u[2]
+		} else if u := re2.FindStringSubmatch(t); u != nil {
+			if _, ok := romanValue(u[1]); !ok && u[2] != "." && u[2] != "" {
+				continue // a word, as in "CIVIL WAR"
+			}
+			level = "numeral"
+			numstr = u[1]
+		} else {
+			continue // not a numbered heading
+		}
+
+		n, err := strconv.Atoi(numstr)
+		if err != nil {
+			var ok bool
+			if n, ok = romanValue(numstr); !ok {
+				report(h, fmt.Sprintf("%s: malformed Roman numeral \"%s\"", level, numstr))
+				continue
+			}
+		}
+
+		st, ok := levels[level]
+		if !ok {
+			st = &seqState{}
+			levels[level] = st
+			levelOrder = append(levelOrder, level)
+		}
+		switch {
+		case st.seen == 0:
+			if n != 1 {
+				report(h, fmt.Sprintf("%s: first number is %s", level, numstr))
+			}
+		case n == 1 && lastLevel != level:
+			// new series under a higher-level heading
+		case n == st.prev:
+			report(h, fmt.Sprintf("%s: %s repeated", level, numstr))
+		case n < st.prev:
+			report(h, fmt.Sprintf("%s: %s out of order (follows %s)", level, numstr, st.prevStr))
+		case n > st.prev+1:
+			report(h, fmt.Sprintf("%s: gap: %s follows %s", level, numstr, st.prevStr))
+		}
+		st.prev, st.prevStr = n, numstr
+		st.seen++
+		lastLevel = level
+	}
+
+	if p.Verbose {
+		for _, level := range levelOrder {
+			rs = append(rs, fmt.Sprintf("  %s: %d headings", level, levels[level].seen))
+		}
+	}
+
+	if count == 0 {
+		rs = append(rs, "  no heading number sequence problems found.")
+		rs[0] = "☲" + rs[0] // style dim
+	} else {
+		rs[0] = "☳" + rs[0] // style black
+	}
+	rs = append(rs, "")
+	rs[len(rs)-1] += "☷" // close style
+	tcec += count
+	return rs
+}
+
+// count the ways a book writes something and report every way but the
+// most common, with the lines it is on. styles gives the style of each
+// occurrence on a line. returns the report and whether styles were mixed
+func styleSurvey(wb []string, what string, styles func(line string) []string) ([]string, bool) {
+	counts := map[string]int{}
+	lines := map[string][]int{}
+	order := []string{} // styles in order of first use
+	for n, line := range wb {
+		for _, st := range styles(line) {
+			if counts[st] == 0 {
+				order = append(order, st)
+			}
+			counts[st]++
+			if l := lines[st]; len(l) == 0 || l[len(l)-1] != n {
+				lines[st] = append(l, n)
+			}
+		}
+	}
+	if len(order) < 2 {
+		return nil, false
+	}
+	sort.SliceStable(order, func(i, j int) bool { return counts[order[i]] > counts[order[j]] })
+
+	rs := []string{}
+	s := []string{}
+	for _, st := range order {
+		s = append(s, fmt.Sprintf("%s (%d)", st, counts[st]))
+	}
+	rs = append(rs, fmt.Sprintf("  mixed %s: %s", what, strings.Join(s, ", ")))
+	for _, st := range order[1:] {
+		rs = append(rs, fmt.Sprintf("    %s:", st))
+		for i, n := range lines[st] {
+			if !p.Verbose && i == 5 {
+				rs = append(rs, fmt.Sprintf("         ... %d more", len(lines[st])-5))
+				break
+			}
+			rs = append(rs, fmt.Sprintf("  %5d: %s", n+1, pt(wb[n])))
+		}
+	}
+	return rs, true
+}
+
+// the count of each variant of a group, for -v. "" if the book has none
+func variantCounts(wb []string, vs []variant) string {
+	counts := map[string]int{}
+	total := 0
 	for _, line := range wb {
-		cnortheast += len(re11.FindAllString(line, -1))
-		cnorthheast += len(re12.FindAllString(line, -1))
-		cnorthwest += len(re13.FindAllString(line, -1))
-		cnorthhwest += len(re14.FindAllString(line, -1))
-		csoutheast += len(re15.FindAllString(line, -1))
-		csouthheast += len(re16.FindAllString(line, -1))
-		csouthwest += len(re17.FindAllString(line, -1))
-		csouthhwest += len(re18.FindAllString(line, -1))
-	}
-
-	cnh := cnortheast + cnorthwest + csoutheast + csouthwest
-	cwh := cnorthheast + cnorthhwest + csouthheast + csouthhwest
-	cshow := false
-	if cnh > 0 && cwh > 0 {
-		rs = append(rs, "compass direction hyphenation inconsistency")
+		for _, v := range variantsOn(line, vs) {
+			counts[v]++
+			total++
+		}
+	}
+	if total == 0 {
+		return ""
+	}
+	s := []string{}
+	for _, v := range vs {
+		s = append(s, fmt.Sprintf("%10s: %3d", v.display(), counts[v.display()]))
+	}
+	return strings.Join(s, " ")
+}
+
+// the variants on a line, once for each time. longer variants are
+// matched first and a shorter one is not counted inside them, so "Mr"
+// is not also found in "Mr."
+func variantsOn(line string, vs []variant) []string {
+	r := []string{}
+	used := []([]int){}
+	isWord := func(c rune) bool { return unicode.IsLetter(c) || unicode.IsNumber(c) }
+	for _, v := range vs {
+		for _, loc := range v.re.FindAllStringIndex(line, -1) {
+			if v.literal {
+				first, _ := utf8.DecodeRuneInString(v.name)
+				last, _ := utf8.DecodeLastRuneInString(v.name)
+				before, _ := utf8.DecodeLastRuneInString(line[:loc[0]])
+				after, _ := utf8.DecodeRuneInString(line[loc[1]:])
+				if isWord(first) && isWord(before) || isWord(last) && isWord(after) {
+					continue // part of a longer word
+				}
+			}
+			inside := false
+			for _, u := range used {
+				inside = inside || loc[0] < u[1] && loc[1] > u[0]
+			}
+			if !inside {
+				used = append(used, loc)
+				r = append(r, v.display())
+			}
+		}
+	}
+	return r
+}
+
+// a variant as it is shown in the report
+func (v variant) display() string {
+	if v.literal {
+		return `"` + v.name + `"`
+	}
+	return "/" + v.name + "/"
+}
+
+// the groups of variants to survey. a suffix group gives one group for
+// each stem that appears in the book with more than one of its suffixes
+func (cg consistencyGroup) expand() [][]variant {
+	if len(cg.suffixes) == 0 {
+		return [][]variant{cg.variants}
+	}
+	lcount := map[string]int{}
+	for w, n := range wordListMapCount {
+		lcount[strings.ToLower(w)] += n
+	}
+	stems := []string{}
+	for w := range lcount {
+		for _, suf := range cg.suffixes {
+			if stem := strings.TrimSuffix(w, suf); stem != w && utf8.RuneCountInString(stem) >= 3 && !contains(stems, stem) {
+				stems = append(stems, stem)
+			}
+		}
+	}
+	sort.Strings(stems)
+	groups := [][]variant{}
+	for _, stem := range stems {
+		vs := []variant{}
+		for _, suf := range cg.suffixes {
+			if lcount[stem+suf] > 0 {
+				vs = append(vs, literalVariant(stem+suf))
+			}
+		}
+		if len(vs) > 1 {
+			groups = append(groups, vs)
+		}
+	}
+	return groups
+}
+
+var reEllipsis = regexp.MustCompile(`\.( ?\.)+|…( ?\.)?`)
+
+// ellipsis styles on a line: unspaced "...", spaced ". . ." or the "…"
+// character. a fourth dot is the period of a sentence ending there, so
+// "....", "... ." and "…." are the same styles as "..." and "…"
+func ellipsisStyles(line string) []string {
+	r := []string{}
+	for _, e := range reEllipsis.FindAllString(line, -1) {
+		sentenceEnd := strings.Count(e, ".") == 4 || strings.HasPrefix(e, "…") && strings.HasSuffix(e, ".")
+		if sentenceEnd {
+			e = strings.TrimSuffix(strings.TrimSuffix(e, "."), " ")
+		}
+		switch e {
+		case "...":
+			r = append(r, `unspaced "..."`)
+		case ". . .":
+			r = append(r, `spaced ". . ."`)
+		case "…":
+			r = append(r, `"…"`)
+		}
+	}
+	return r
+}
+
+var reDashRun = regexp.MustCompile(`—+|-+`)
+
+// em-dash styles on a line: closed up to the words on both sides or
+// spaced on both sides. "--" is the em-dash with the ASCII convention
+func emDashStyles(line string) []string {
+	dash, closed, spaced := "—", `"a—b"`, `"a — b"`
+	if dashStyle == "ASCII" {
+		dash, closed, spaced = "--", `"a--b"`, `"a -- b"`
+	}
+	r := []string{}
+	for _, loc := range reDashRun.FindAllStringIndex(line, -1) {
+		if line[loc[0]:loc[1]] != dash {
+			continue
+		}
+		before, after := line[:loc[0]], line[loc[1]:]
+		if before == "" || after == "" {
+			continue
+		}
+		sb, sa := strings.HasSuffix(before, " "), strings.HasPrefix(after, " ")
+		if !sb && !sa {
+			r = append(r, closed)
+		}
+		if sb && sa && strings.TrimSpace(before) != "" && strings.TrimSpace(after) != "" {
+			r = append(r, spaced)
+		}
+	}
+	return r
+}
+
+// book-level checks
+func tcBookLevel(wb []string) []string {
+	rs := []string{}
+	rs = append(rs, "----- book level checks -----------------------------------------------")
+	rs = append(rs, "")
+	count := 0
+	needsep := false
+	// check: straight and curly quotes mixed
+	if m['\''] > 0 && (m['‘'] > 0 || m['’'] > 0) {
+		rs = append(rs, "  both straight and curly ◨single◧ quotes found in text")
+		count++
 		needsep = true
-		cshow = true
-		count += 1
 	}
 
-	if cshow || p.Verbose {
-		rs = append(rs, fmt.Sprintf("%10s: %3d %10s: %3d", "northeast", cnortheast, "north-east", cnorthheast))
-		rs = append(rs, fmt.Sprintf("%10s: %3d %10s: %3d", "northwest", cnorthwest, "north-west", cnorthhwest))
-		rs = append(rs, fmt.Sprintf("%10s: %3d %10s: %3d", "southeast", csoutheast, "south-east", csouthheast))
-		rs = append(rs, fmt.Sprintf("%10s: %3d %10s: %3d", "southwest", csouthwest, "south-west", csouthhwest))
+	if m['"'] > 0 && (m['“'] > 0 || m['”'] > 0) {
+		rs = append(rs, "  both straight and curly ◨double◧ quotes found in text")
+		count++
 		needsep = true
 	}
+
 	if needsep {
 		rs = append(rs, "")
 		needsep = false
 	}
 
+	// ----- variant groups from the consistency files -----
+	vcounts := []string{} // counts of the groups that are not mixed, for -v
+	for _, cg := range consistencyGroups {
+		for _, vs := range cg.expand() {
+			if t, mixed := styleSurvey(wb, "forms", func(line string) []string { return variantsOn(line, vs) }); mixed {
+				rs = append(rs, t...)
+				rs = append(rs, "")
+				count++
+			} else if p.Verbose {
+				if t := variantCounts(wb, vs); t != "" {
+					vcounts = append(vcounts, t)
+				}
+			}
+		}
+	}
+	if len(vcounts) > 0 {
+		rs = append(rs, vcounts...)
+		rs = append(rs, "")
+	}
+
 	// ----- check American and British title punctuation mixed -----
-	re01 = regexp.MustCompile(`(?i)\P{L}Mr\.`)
-	re02 = regexp.MustCompile(`(?i)\P{L}Mr\p{Zs}`)
-	re03 = regexp.MustCompile(`(?i)\P{L}Mrs\.`)
-	re04 = regexp.MustCompile(`(?i)\P{L}Mrs\p{Zs}`)
-	re05 = regexp.MustCompile(`(?i)\P{L}Dr\.`)
-	re06 = regexp.MustCompile(`(?i)\P{L}Dr\p{Zs}`)
+	// each title on its own is a consistency group. here the titles are
+	// taken together
+	re01 := regexp.MustCompile(`(?i)(^|\P{L})Mr\.`)
+	re02 := regexp.MustCompile(`(?i)(^|\P{L})Mr\p{Zs}`)
+	re03 := regexp.MustCompile(`(?i)(^|\P{L})Mrs\.`)
+	re04 := regexp.MustCompile(`(?i)(^|\P{L})Mrs\p{Zs}`)
+	re05 := regexp.MustCompile(`(?i)(^|\P{L})Dr\.`)
+	re06 := regexp.MustCompile(`(?i)(^|\P{L})Dr\p{Zs}`)
 
 	count_mr_period, count_mr_space, count_mrs_period, count_mrs_space, count_dr_period, count_dr_space := 0, 0, 0, 0, 0, 0
 	for _, line := range wb {
@@ -2632,32 +5290,10 @@ func tcBookLevel(wb []string) []string {
 		count_dr_space += len(re06.FindAllString(line, -1))
 	}
 
-	mabreported := false
-	if count_mr_period > 0 && count_mr_space > 0 {
-		rs = append(rs,
-			fmt.Sprintf("  both \"Mr.\" (%d) and \"Mr\" (%d) found in text",
-				count_mr_period, count_mr_space))
-		count++
-		mabreported = true
-		needsep = true
-	}
-	if count_mrs_period > 0 && count_mrs_space > 0 {
-		rs = append(rs,
-			fmt.Sprintf("  both \"Mrs.\" (%d) and \"Mrs\" (%d) found in text",
-				count_mrs_period, count_mrs_space))
-		count++
-		mabreported = true
-		needsep = true
-	}
-	if count_dr_period > 0 && count_dr_space > 0 {
-		rs = append(rs,
-			fmt.Sprintf("  both \"Dr.\" (%d) and \"Dr\" (%d) found in text",
-				count_dr_period, count_dr_space))
-		count++
-		mabreported = true
-		needsep = true
-	}
-	// if mixed and not already reported
+	// if mixed and not already reported for a single title
+	mabreported := count_mr_period > 0 && count_mr_space > 0 ||
+		count_mrs_period > 0 && count_mrs_space > 0 ||
+		count_dr_period > 0 && count_dr_space > 0
 	showmaball := false
 	if !mabreported && (count_mr_period+count_mrs_period+count_dr_period > 0) &&
 		(count_mr_space+count_mrs_space+count_dr_space > 0) {
@@ -2702,6 +5338,18 @@ func tcBookLevel(wb []string) []string {
 		needsep = false
 	}
 
+	// ----- ellipsis and em-dash styles mixed -----
+	if t, mixed := styleSurvey(wb, "ellipsis styles", ellipsisStyles); mixed {
+		rs = append(rs, t...)
+		rs = append(rs, "")
+		count++
+	}
+	if t, mixed := styleSurvey(wb, "em-dash spacing", emDashStyles); mixed {
+		rs = append(rs, t...)
+		rs = append(rs, "")
+		count++
+	}
+
 	// check for repeated lines at least 5 characters long.
 	limit := len(wb) - 1
 	for n, _ := range wb {
@@ -2971,13 +5619,12 @@ func tcParaLevel() []string {
 	}
 
 	// ------------------------------------------------------------------------
-	// check: common he/be, hut/but and had/bad checks
+	// check: common he/be checks
 	//        jeebies is run separately with different algorithm (three word forms)
+	//        and checks the other confusion pairs in pairlist.txt
 
 	const (
-		HADBADPATTERN = `\bi bad\b|\byou bad\b|\bhe bad\b|\bshe bad\b|\bthey bad\b|\ba had\b|\bthe had\b`
-		HUTBUTPATTERN = `(, hut\P{L})|(; hut\P{L})`
-		HEBEPATTERN   = `\bto he\b|\bis be\b|\bbe is\b|\bwas be\b|\bbe would\b|\bbe could\b`
+		HEBEPATTERN = `\bto he\b|\bis be\b|\bbe is\b|\bwas be\b|\bbe would\b|\bbe could\b`
 	)
 
 	re_hebe := regexp.MustCompile(HEBEPATTERN)
@@ -3000,46 +5647,6 @@ func tcParaLevel() []string {
 		rs = append(rs, fmt.Sprintf("    ...%d more", sscnt-RLIMIT))
 	}
 
-	re_hadbad := regexp.MustCompile(HADBADPATTERN)
-	sscnt = 0
-	for _, para := range pbuf {
-		lpara := strings.ToLower(para)
-		loc := re_hadbad.FindAllStringIndex(lpara, -1)
-		for _, aloc := range loc {
-			if sscnt == 0 {
-				rs = append(rs, "  query: had/bad")
-				count++
-			}
-			sscnt++
-			if sscnt < RLIMIT || p.Verbose {
-				rs = append(rs, "    "+getParaSegment(para, aloc[0]))
-			}
-		}
-	}
-	if sscnt > RLIMIT && !p.Verbose {
-		rs = append(rs, fmt.Sprintf("    ...%d more", sscnt-RLIMIT))
-	}
-
-	re_hutbut := regexp.MustCompile(HUTBUTPATTERN)
-	sscnt = 0
-	for _, para := range pbuf {
-		lpara := strings.ToLower(para)
-		loc := re_hutbut.FindAllStringIndex(lpara, -1)
-		for _, aloc := range loc {
-			if sscnt == 0 {
-				rs = append(rs, "  query: hut/but")
-				count++
-			}
-			sscnt++
-			if sscnt < RLIMIT || p.Verbose {
-				rs = append(rs, "    "+getParaSegment(para, aloc[0]))
-			}
-		}
-	}
-	if sscnt > RLIMIT && !p.Verbose {
-		rs = append(rs, fmt.Sprintf("    ...%d more", sscnt-RLIMIT))
-	}
-
 	// ------------------------------------------------------------------------
 	// check: paragraph endings, punctuation-style aware
 
@@ -3198,7 +5805,10 @@ func tcGutChecks(wb []string) []string {
 		}
 	}
 
-	for n, line := range wb {
+	// match against the text with [Greek: ] transliterations masked,
+	// but report the line as written
+	mb := maskGreek(wb, findGreekBlocks(wb))
+	for n, line := range mb {
 
 		if re0021.MatchString(line) &&
 			!re0021a.MatchString(line) &&
@@ -3207,21 +5817,21 @@ func tcGutChecks(wb []string) []string {
 			!re0021d.MatchString(line) &&
 			!re0021e.MatchString(line) &&
 			!re0021f.MatchString(line) {
-			gcreports = append(gcreports, reportln{"mixed letters and numbers in word", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+			gcreports = append(gcreports, reportln{"mixed letters and numbers in word", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
 		}
 
 		// if re0026.MatchString(line) {
-		//   gcreports = append(gcreports, reportln{"full stop followed by letter", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+		//   gcreports = append(gcreports, reportln{"full stop followed by letter", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
 		//}
 
 		if re0000.MatchString(line) {
-			gcreports = append(gcreports, reportln{"opening square bracket followed by other than I, G, M, S or number", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+			gcreports = append(gcreports, reportln{"opening square bracket followed by other than I, G, M, S or number", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
 		}
 		if re0001.MatchString(line) {
-			gcreports = append(gcreports, reportln{"punctuation after 'the'", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+			gcreports = append(gcreports, reportln{"punctuation after 'the'", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
 		}
 		if re0002.MatchString(line) {
-			gcreports = append(gcreports, reportln{"punctuation error", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+			gcreports = append(gcreports, reportln{"punctuation error", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
 		}
 		// check each word separately on this line
 		for _, word := range lwl[n] {
@@ -3230,7 +5840,7 @@ func tcGutChecks(wb []string) []string {
 			// or after the second character if the first char is "’"
 			// but not if the word is in the good word list or if it occurs more than once
 			reportme := false
-			if wordListMapCount[word] < 2 && !inGoodWordList(word) {
+			if wordListMapCount[word] < 2 && !inGoodWordList(word, "") {
 				if strings.HasPrefix(word, "’") {
 					if re0003a2.MatchString(word) && re0003b2.MatchString(word) {
 						reportme = true
@@ -3242,53 +5852,53 @@ func tcGutChecks(wb []string) []string {
 				}
 			}
 			if reportme {
-				linetmp := strings.Replace(line, word, fmt.Sprintf("☰%s☷", word), -1)
+				linetmp := strings.Replace(wb[n], word, fmt.Sprintf("☰%s☷", word), -1)
 				gcreports = append(gcreports, reportln{"mixed case within word", fmt.Sprintf("  %5d: %s", n+1, wraptext9(linetmp))})
 			}
 
 			if len(word) > 2 {
 				last2 := word[len(word)-2:]
 				if re0003c.MatchString(last2) {
-					linetmp := strings.Replace(line, word, fmt.Sprintf("☰%s☷", word), -1)
+					linetmp := strings.Replace(wb[n], word, fmt.Sprintf("☰%s☷", word), -1)
 					gcreports = append(gcreports, reportln{fmt.Sprintf("query word ending with %s", last2), fmt.Sprintf("  %5d: %s", n+1, wraptext9(linetmp))})
 				}
 				first2 := word[:2]
 				if re0003d.MatchString(first2) {
-					linetmp := strings.Replace(line, word, fmt.Sprintf("☰%s☷", word), -1)
+					linetmp := strings.Replace(wb[n], word, fmt.Sprintf("☰%s☷", word), -1)
 					gcreports = append(gcreports, reportln{fmt.Sprintf("query word starting with %s", first2), fmt.Sprintf("  %5d: %s", n+1, wraptext9(linetmp))})
 				}
 			}
 		}
 		if re0006.MatchString(line) {
-			gcreports = append(gcreports, reportln{"single character line", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+			gcreports = append(gcreports, reportln{"single character line", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
 		}
 		if re0007.MatchString(line) {
-			gcreports = append(gcreports, reportln{"broken hyphenation", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+			gcreports = append(gcreports, reportln{"broken hyphenation", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
 		}
 		if re0008a.MatchString(line) ||
 			re0008b.MatchString(line) ||
 			re0008c.MatchString(line) ||
 			re0008d.MatchString(line) {
-			gcreports = append(gcreports, reportln{"comma spacing", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+			gcreports = append(gcreports, reportln{"comma spacing", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
 		}
 		if re0010.MatchString(line) {
-			gcreports = append(gcreports, reportln{"date format", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+			gcreports = append(gcreports, reportln{"date format", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
 		}
 		if re0011.MatchString(line) {
-			gcreports = append(gcreports, reportln{"I/! check", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+			gcreports = append(gcreports, reportln{"I/! check", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
 		}
 		if re0012.MatchString(line) {
-			gcreports = append(gcreports, reportln{"disjointed contraction", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+			gcreports = append(gcreports, reportln{"disjointed contraction", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
 		}
 		if re0013.MatchString(line) {
-			gcreports = append(gcreports, reportln{"title abbreviation comma", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+			gcreports = append(gcreports, reportln{"title abbreviation comma", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
 		}
 		if re0014.MatchString(line) {
-			gcreports = append(gcreports, reportln{"spaced punctuation", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+			gcreports = append(gcreports, reportln{"spaced punctuation", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
 		}
 		if re0016.MatchString(line) {
 			if abandonedTagCount < 10 {
-				gcreports = append(gcreports, reportln{"abandoned HTML tag", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+				gcreports = append(gcreports, reportln{"abandoned HTML tag", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
 			}
 			if abandonedTagCount == 10 {
 				gcreports = append(gcreports, reportln{"abandoned HTML tag", fmt.Sprintf("  %5d: %s", 99999, "...more")})
@@ -3296,13 +5906,13 @@ func tcGutChecks(wb []string) []string {
 			abandonedTagCount++
 		}
 		// if re0017.MatchString(line) {
-		//      gcreports = append(gcreports, reportln{"ellipsis check", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+		//      gcreports = append(gcreports, reportln{"ellipsis check", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
 		// }
 		if re0018.MatchString(line) {
-			gcreports = append(gcreports, reportln{"quote error (context)", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+			gcreports = append(gcreports, reportln{"quote error (context)", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
 		}
 		if re0019.MatchString(line) {
-			gcreports = append(gcreports, reportln{"standalone 0", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+			gcreports = append(gcreports, reportln{"standalone 0", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
 		}
 		if re0020a.MatchString(line) &&
 			!re0020b.MatchString(line) &&
@@ -3311,91 +5921,710 @@ func tcGutChecks(wb []string) []string {
 			!re0020e.MatchString(line) &&
 			!re0020f.MatchString(line) &&
 			!re0020g.MatchString(line) {
-			gcreports = append(gcreports, reportln{"standalone 1", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+			gcreports = append(gcreports, reportln{"standalone 1", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
+		}
+		if re0022.MatchString(line) {
+			gcreports = append(gcreports, reportln{"trailing space on line", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
+		}
+		if re0023.MatchString(line) {
+			gcreports = append(gcreports, reportln{"abbreviation &c without period", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
+		}
+		if re0024.MatchString(line) {
+			gcreports = append(gcreports, reportln{"line starts with suspect punctuation", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
+		}
+		if re0025.MatchString(line) {
+			gcreports = append(gcreports, reportln{"line that starts with hyphen and then non-hyphen", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
+		}
+
+		// begin non-regexp based
+		if strings.Contains(line, "Blank Page") {
+			gcreports = append(gcreports, reportln{"Blank Page placeholder found", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
+		}
+		if strings.Contains(line, "—-") || strings.Contains(line, "-—") {
+			gcreports = append(gcreports, reportln{"mixed hyphen/dash", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
+		}
+		if strings.Contains(line, "\u00A0") {
+			gcreports = append(gcreports, reportln{"non-breaking space", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
+		}
+		if strings.Contains(line, "\u00AD") {
+			gcreports = append(gcreports, reportln{"soft hyphen", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
+		}
+		if strings.Contains(line, "\u0009") {
+			gcreports = append(gcreports, reportln{"tab character", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
+		}
+		if strings.Contains(line, "&") {
+			gcreports = append(gcreports, reportln{"ampersand character", fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
+		}
+		lcline := strings.ToLower(line)
+		if re_comma.MatchString(lcline) {
+			gcreports = append(gcreports, reportln{fmt.Sprintf("unexpected comma after word"), fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
+		}
+		if re_period.MatchString(lcline) {
+			gcreports = append(gcreports, reportln{fmt.Sprintf("unexpected period after word"), fmt.Sprintf("  %5d: %s", n+1, wraptext9(wb[n]))})
+		}
+	}
+
+	sort.Slice(gcreports, func(i, j int) bool { return gcreports[i].sourceline < gcreports[j].sourceline })
+	sort.SliceStable(gcreports, func(i, j int) bool { return gcreports[i].rpt < gcreports[j].rpt })
+
+	if abandonedTagCount > 10 {
+		rs = append(rs, fmt.Sprintf("note: source file not plain text. %d lines with markup", abandonedTagCount))
+	}
+
+	if len(gcreports) > 0 {
+		rrpt_last := ""
+		ctr := 0 // count this report
+		for _, rpt := range gcreports {
+			if rpt.rpt != rrpt_last {
+				// a report with a new report type
+				// first one gets new header line
+				rs = append(rs, fmt.Sprintf("%s\n%s", rpt.rpt, rpt.sourceline))
+				ctr = 1
+				rrpt_last = rpt.rpt
+				continue
+			} else {
+				// a report with the same report type
+				// handle continuation line
+				s := strings.Replace(rpt.sourceline, "99999: ", "       ", -1)
+				if p.Verbose || ctr < 5 {
+					rs = append(rs, fmt.Sprintf("%s", s)) // subsequent reports
+				}
+				if !p.Verbose && ctr == 5 {
+					rs = append(rs, fmt.Sprintf("         ... more"))
+				}
+				ctr += 1
+			}
+		}
+	}
+
+	if len(gcreports) == 0 {
+		rs = append(rs, "   no special situation reports.")
+		rs[0] = "☲" + rs[0] // style dim
+	} else {
+		rs[0] = "☳" + rs[0] // style black
+	}
+	rs = append(rs, "")
+	rs[len(rs)-1] += "☷" // close style
+	tcec += len(gcreports)
+	return rs
+}
+
+// a word of a paragraph and where it starts
+type paraWord struct {
+	word        string
+	para, where int
+}
+
+var reParaWord = regexp.MustCompile(`(?:Mc?‘)?\p{L}+(?:[’'-]\p{L}+)*`) // M‘ and Mc‘ start names
+
+// the words of two or more letters in the middle of sentences. a word at
+// the start of a paragraph, after . ! ? or : or after an opening quote or
+// bracket may be capitalized for its position and is left out. headings,
+// where every longer word is capitalized, are skipped
+func midSentenceWords(pb []string) []paraWord {
+	pws := []paraWord{}
+	for n, para := range pb {
+		locs := reParaWord.FindAllStringIndex(para, -1)
+		heading := true
+		for _, loc := range locs {
+			w := para[loc[0]:loc[1]]
+			if r, _ := utf8.DecodeRuneInString(w); utf8.RuneCountInString(w) > 3 && !unicode.IsUpper(r) {
+				heading = false
+				break
+			}
+		}
+		if heading {
+			continue
+		}
+		// the offsets in the paragraph of its indented lines, as in verse,
+		// where every line may start with a capital
+		indented := []int{}
+		if n < len(pbufStart) {
+			off := 0
+			for ln := pbufStart[n]; ln < len(wbuf) && wbuf[ln] != ""; ln++ {
+				if strings.HasPrefix(wbuf[ln], " ") {
+					indented = append(indented, off)
+				}
+				off += len(wbuf[ln]) + 1
+			}
+		}
+		for _, loc := range locs {
+			w := para[loc[0]:loc[1]]
+			if utf8.RuneCountInString(w) < 2 {
+				continue
+			}
+			// the first word of an indented line
+			lineStart := false
+			for _, off := range indented {
+				if off <= loc[0] && strings.TrimSpace(para[off:loc[0]]) == "" {
+					lineStart = true
+				}
+			}
+			if lineStart {
+				continue
+			}
+			// the character before the word, skipping spaces
+			before := strings.TrimRight(para[:loc[0]], " ")
+			if before == "" {
+				continue
+			}
+			prev, _ := utf8.DecodeLastRuneInString(before)
+			if strings.ContainsRune(".!?:“‘\"'([", prev) {
+				continue
+			}
+			if strings.ContainsRune("”’\"')]", prev) {
+				// a closing quote after the end of a sentence
+				t := strings.TrimRight(before, "”’\"')]")
+				if t2, _ := utf8.DecodeLastRuneInString(t); strings.ContainsRune(".!?:", t2) {
+					continue
+				}
+			}
+			pws = append(pws, paraWord{w, n, loc[0]})
+		}
+	}
+	return pws
+}
+
+// words starting with a vowel letter and a consonant sound, which take "a".
+// "one" itself is matched only as a word or the start of a compound
+var aPrefixes = []string{"once", "ouija", "eu", "ewe", "use", "usu",
+	"ute", "uti", "uto", "ubiq", "ure", "uri", "uro", "unio", "uniq", "unit", "univ",
+	"unif", "unic", "unis", "unil", "unanim"}
+
+// words starting with a silent h, which take "an"
+var anPrefixes = []string{"hour", "honest", "honour", "honor", "heir"}
+
+// letters whose names start with a vowel sound, for acronyms
+const vowelLetterNames = "aefhilmnorsx"
+
+var reOrdinal = regexp.MustCompile(`^(\d[\d,]*)(st|nd|rd|th|d)$`)
+
+// the article a word takes, "a" or "an". next is the word after it, if
+// any. with period usage "" is returned for words where either is
+// accepted, as "an hotel" and "an union"
+func articleFor(word, next string) string {
+	lw := strings.ToLower(word)
+	first, _ := utf8.DecodeRuneInString(lw)
+
+	// numbers: an 8, an 11, an 18, an 80, an 11th-century, an 18th, and
+	// years read in hundreds: an 1800-mile march, an 1100
+	if unicode.IsDigit(first) {
+		if i := strings.Index(lw, "-"); i > 0 {
+			lw = lw[:i]
+		}
+		lw = reOrdinal.ReplaceAllString(lw, "$1")
+		lw = strings.TrimRight(lw, "s’'") // the 1880s
+		hundreds := len(lw) == 4 && strings.Trim(lw, "0123456789") == ""
+		if first == '8' || lw == "11" || lw == "18" || strings.HasPrefix(lw, "11,") || strings.HasPrefix(lw, "18,") ||
+			hundreds && (strings.HasPrefix(lw, "11") || strings.HasPrefix(lw, "18")) {
+			return "an"
+		}
+		return "a"
+	}
+	// acronyms and letters, as "an MP", "an M.P." and "an x-ray", go by
+	// the name of the first letter. an all capitals word is taken as an
+	// acronym only if it is short and the next word is not in capitals
+	// too, as it is in a heading
+	r := []rune(word)
+	acronym := word == strings.ToUpper(word) && len(r) <= 4 &&
+		!(utf8.RuneCountInString(next) > 1 && next == strings.ToUpper(next))
+	if acronym || strings.ContainsRune(word, '.') || len(r) > 1 && r[1] == '-' {
+		if strings.ContainsRune(vowelLetterNames, first) {
+			return "an"
+		}
+		return "a"
+	}
+	if lw == "one" || strings.HasPrefix(lw, "one-") {
+		if p.Articles == "period" {
+			return ""
+		}
+		return "a"
+	}
+	for _, pre := range anPrefixes {
+		if strings.HasPrefix(lw, pre) {
+			return "an"
+		}
+	}
+	for _, pre := range aPrefixes {
+		if strings.HasPrefix(lw, pre) {
+			if p.Articles == "period" {
+				return ""
+			}
+			return "a"
+		}
+	}
+	if strings.ContainsRune("aeiou", first) {
+		return "an"
+	}
+	if first == 'h' && p.Articles == "period" {
+		return ""
+	}
+	return "a"
+}
+
+// a/an agreement check
+// an article that does not agree with the sound of the next word
+func tcArticles(pb []string) []string {
+	rs := []string{}
+	heading := fmt.Sprintf("----- a/an agreement check (%s usage) ", p.Articles)
+	rs = append(rs, heading+strings.Repeat("-", 80-len(heading)))
+	rs = append(rs, "")
+
+	re := regexp.MustCompile(`(^|[^\p{L}\p{N}’'-])([Aa]n?) +([\p{L}\p{N}][\p{L}\p{N}’'.,-]*)`)
+
+	// with more than one language, the paragraphs with the passages in
+	// the other languages blanked out, at the same byte offsets
+	var mask []string
+	if profiles, _ := languageProfiles(); len(profiles) > 1 && len(pbufStart) == len(pb) {
+		masked, _ := tagLanguages(wbuf, profiles)
+		mlines := masked[profiles[0].lang]
+		for n := range pb {
+			t := []string{}
+			for ln := pbufStart[n]; ln < len(wbuf) && wbuf[ln] != ""; ln++ {
+				t = append(t, mlines[ln])
+			}
+			mask = append(mask, strings.Join(t, " "))
+		}
+	}
+
+	aReports := []string{}  // "a" before a vowel sound
+	anReports := []string{} // "an" before a consonant sound
+	for n, para := range pb {
+		for _, m := range re.FindAllStringSubmatchIndex(para, -1) {
+			if mask != nil && len(mask[n]) == len(para) && mask[n][m[4]] == ' ' {
+				continue // in a passage in another language
+			}
+			article := strings.ToLower(para[m[4]:m[5]])
+			if strings.HasSuffix(strings.TrimSpace(para[:m[4]]), "letter") {
+				continue // the letter a
+			}
+			word := strings.TrimRight(para[m[6]:m[7]], ".,")
+			next := reParaWord.FindString(para[m[7]:])
+			want := articleFor(word, next)
+			if want == "" || want == article {
+				continue
+			}
+			end := m[6] + len(word)
+			t := para[:m[4]] + "☰" + para[m[4]:end] + "☷" + para[end:]
+			s := fmt.Sprintf("  %5d: %s", paraLine(n, m[4])+1, pt(getParaSegment(t, m[4])))
+			if article == "a" {
+				aReports = append(aReports, s)
+			} else {
+				anReports = append(anReports, s)
+			}
+		}
+	}
+
+	count := 0
+	for _, g := range []struct {
+		heading string
+		reports []string
+	}{
+		{"  \"a\" before a vowel sound", aReports},
+		{"  \"an\" before a consonant sound", anReports},
+	} {
+		if len(g.reports) == 0 {
+			continue
+		}
+		rs = append(rs, g.heading)
+		for i, s := range g.reports {
+			if !p.Verbose && i == 5 {
+				rs = append(rs, fmt.Sprintf("         ... %d more", len(g.reports)-5))
+				break
+			}
+			rs = append(rs, s)
+		}
+		rs = append(rs, "")
+		count += len(g.reports)
+	}
+
+	if count == 0 {
+		rs = append(rs, "  no a/an agreement problems found.")
+		rs[0] = "☲" + rs[0] // style dim
+		rs = append(rs, "")
+	} else {
+		rs[0] = "☳" + rs[0] // style black
+	}
+	rs[len(rs)-1] += "☷" // close style
+	tcec += count
+	return rs
+}
+
+// the line of the working buffer a byte offset in a paragraph of pbuf is
+// on, 0-based. the lines of a paragraph are joined with one space
+func paraLine(para, where int) int {
+	n := pbufStart[para]
+	for off := len(wbuf[n]) + 1; off <= where && n+1 < len(wbuf); off += len(wbuf[n]) + 1 {
+		n++
+	}
+	return n
+}
+
+// words capitalized for emphasis or in titles as often as for any sense of
+// their own. never reported by the capitalization check
+var functionWords = map[string]bool{
+	"a": true, "an": true, "the": true, "and": true, "but": true, "or": true,
+	"nor": true, "for": true, "yet": true, "so": true, "of": true, "in": true,
+	"on": true, "at": true, "to": true, "by": true, "with": true, "from": true,
+	"as": true, "if": true, "that": true, "this": true, "it": true, "is": true,
+	"was": true, "be": true, "not": true, "no": true, "all": true, "then": true,
+	"when": true, "where": true, "who": true, "what": true, "which": true,
+	"he": true, "she": true, "we": true, "they": true, "you": true, "his": true,
+	"her": true, "its": true, "their": true, "my": true, "our": true, "your": true,
+}
+
+// capitalization check
+// words found in the middle of sentences both capitalized and in lower
+// case, as "the Colonel" and "the colonel"
+func tcCapitalization(pb []string) []string {
+	rs := []string{}
+	rs = append(rs, "----- capitalization check ---------------------------------------------------")
+	rs = append(rs, "")
+
+	capUses := map[string][]paraWord{} // by the lower case form of the word
+	lowUses := map[string][]paraWord{}
+	for _, pw := range midSentenceWords(pb) {
+		lw := strings.ToLower(pw.word)
+		first, size := utf8.DecodeRuneInString(pw.word)
+		switch {
+		case pw.word == lw:
+			lowUses[lw] = append(lowUses[lw], pw)
+		case unicode.IsUpper(first) && pw.word[size:] == strings.ToLower(pw.word[size:]):
+			capUses[lw] = append(capUses[lw], pw)
+		}
+	}
+
+	words := []string{}
+	for lw := range capUses {
+		if len(lowUses[lw]) > 0 && !functionWords[lw] {
+			words = append(words, lw)
+		}
+	}
+	sort.Strings(words)
+
+	count := 0
+	for _, lw := range words {
+		cu, lu := capUses[lw], lowUses[lw]
+		rs = append(rs, fmt.Sprintf("  %s (%d) / %s (%d)", cu[0].word, len(cu), lw, len(lu)))
+		minority := lu
+		if len(cu) <= len(lu) {
+			minority = cu
+		}
+		for i, u := range minority {
+			if !p.Verbose && i == 5 {
+				rs = append(rs, fmt.Sprintf("         ... %d more", len(minority)-5))
+				break
+			}
+			para := pb[u.para]
+			end := u.where + len(u.word)
+			para = para[:u.where] + "☰" + para[u.where:end] + "☷" + para[end:]
+			rs = append(rs, fmt.Sprintf("  %5d: %s", paraLine(u.para, u.where)+1, pt(getParaSegment(para, u.where))))
+		}
+		rs = append(rs, "")
+		count++
+	}
+
+	if count == 0 {
+		rs = append(rs, "  no capitalization inconsistencies found.")
+		rs[0] = "☲" + rs[0] // style dim
+		rs = append(rs, "")
+	} else {
+		rs[0] = "☳" + rs[0] // style black
+	}
+	rs[len(rs)-1] += "☷" // close style
+	tcec += count
+	return rs
+}
+
+// a name reduced for comparison: lower case, without apostrophes, and
+// with the Mc and M’ prefixes written Mac
+func nameKey(name string) string {
+	k := strings.ToLower(name)
+	for _, pre := range []string{"m’", "m'", "m‘", "mc"} {
+		if strings.HasPrefix(k, pre) {
+			k = "mac" + k[len(pre):]
+			break
+		}
+	}
+	return strings.NewReplacer("’", "", "'", "", "‘", "").Replace(k)
+}
+
+// first letters that spell the same sound in names: Catherine and
+// Katherine, Isaac and Jsaac, Ulysses and Vlysses
+var nameInitials = strings.NewReplacer("c", "k", "j", "i", "v", "u")
+
+// proper name variant check
+// capitalized words in the middle of sentences, taken to be names, are
+// grouped when they are the same apart from case, apostrophes and the
+// Mac prefix, or when they are one edit apart. names of five or more
+// letters are compared by edit distance, each with the names of the same
+// first letter, taking C and K, I and J, U and V as the same, and about
+// the same length. a name one edit from another is reported only if it
+// is rare beside it, so Louis and Louise both used freely are not
+func tcNameVariants(pb []string) []string {
+	rs := []string{}
+	rs = append(rs, "----- proper name variant check -----------------------------------------------")
+	rs = append(rs, "")
+
+	// the names, without any possessive ending
+	names := []string{}
+	for _, pw := range midSentenceWords(pb) {
+		first, _ := utf8.DecodeRuneInString(pw.word)
+		if !unicode.IsUpper(first) || pw.word == strings.ToUpper(pw.word) {
+			continue
 		}
-		if re0022.MatchString(line) {
-			gcreports = append(gcreports, reportln{"trailing space on line", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+		name := strings.TrimSuffix(strings.TrimSuffix(pw.word, "’s"), "'s")
+		if !contains(names, name) {
+			names = append(names, name)
 		}
-		if re0023.MatchString(line) {
-			gcreports = append(gcreports, reportln{"abbreviation &c without period", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+	}
+	sort.Strings(names)
+
+	// names with the same key are one group to begin with
+	keys := []string{}
+	byKey := map[string][]string{}
+	for _, name := range names {
+		k := nameKey(name)
+		if _, ok := byKey[k]; !ok {
+			keys = append(keys, k)
 		}
-		if re0024.MatchString(line) {
-			gcreports = append(gcreports, reportln{"line starts with suspect punctuation", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+		byKey[k] = append(byKey[k], name)
+	}
+
+	// then keys one edit apart are joined
+	parent := map[string]string{}
+	var root func(k string) string
+	root = func(k string) string {
+		if pk, ok := parent[k]; ok && pk != k {
+			return root(pk)
 		}
-		if re0025.MatchString(line) {
-			gcreports = append(gcreports, reportln{"line that starts with hyphen and then non-hyphen", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+		return k
+	}
+	bucket := map[string][]string{} // first letter and length
+	for _, k := range keys {
+		rk := []rune(k)
+		if len(rk) < 5 {
+			continue
 		}
-
-		// begin non-regexp based
-		if strings.Contains(line, "Blank Page") {
-			gcreports = append(gcreports, reportln{"Blank Page placeholder found", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+		initial := nameInitials.Replace(string(rk[0]))
+		for d := -1; d <= 1; d++ {
+			for _, k2 := range bucket[fmt.Sprintf("%s%d", initial, len(rk)+d)] {
+				// a plural is not a variant
+				if k == k2+"s" || k2 == k+"s" {
+					continue
+				}
+				if levenshtein(rk, []rune(k2)) <= 1 {
+					parent[root(k)] = root(k2)
+				}
+			}
 		}
-		if strings.Contains(line, "—-") || strings.Contains(line, "-—") {
-			gcreports = append(gcreports, reportln{"mixed hyphen/dash", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+		b := fmt.Sprintf("%s%d", initial, len(rk))
+		bucket[b] = append(bucket[b], k)
+	}
+	clusters := map[string][]string{}
+	croots := []string{}
+	for _, k := range keys {
+		r := root(k)
+		if _, ok := clusters[r]; !ok {
+			croots = append(croots, r)
 		}
-		if strings.Contains(line, "\u00A0") {
-			gcreports = append(gcreports, reportln{"non-breaking space", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+		clusters[r] = append(clusters[r], byKey[k]...)
+	}
+
+	// occurrences of a name, with its possessive
+	uses := func(name string) int {
+		return wordListMapCount[name] + wordListMapCount[name+"’s"] + wordListMapCount[name+"'s"]
+	}
+
+	// a spelling one edit from the most used one is a variant if it is used
+	// at most a fifth as often
+	const rareRatio = 5
+
+	count := 0
+	for _, r := range croots {
+		cl := clusters[r]
+		if len(cl) < 2 {
+			continue
 		}
-		if strings.Contains(line, "\u00AD") {
-			gcreports = append(gcreports, reportln{"soft hyphen", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+		sort.SliceStable(cl, func(i, j int) bool { return uses(cl[i]) > uses(cl[j]) })
+		kept := []string{cl[0]}
+		for _, name := range cl[1:] {
+			if nameKey(name) == nameKey(cl[0]) || uses(name)*rareRatio <= uses(cl[0]) {
+				kept = append(kept, name)
+			}
 		}
-		if strings.Contains(line, "\u0009") {
-			gcreports = append(gcreports, reportln{"tab character", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+		if cl = kept; len(cl) < 2 {
+			continue
 		}
-		if strings.Contains(line, "&") {
-			gcreports = append(gcreports, reportln{"ampersand character", fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+		s := []string{}
+		for _, name := range cl {
+			s = append(s, fmt.Sprintf("%s (%d)", name, uses(name)))
 		}
-		lcline := strings.ToLower(line)
-		if re_comma.MatchString(lcline) {
-			gcreports = append(gcreports, reportln{fmt.Sprintf("unexpected comma after word"), fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+		rs = append(rs, "  "+strings.Join(s, ", "))
+		for _, name := range cl[1:] {
+			where := wordListMapLines[name]
+			if where == "" {
+				where = wordListMapLines[name+"’s"]
+			}
+			if where == "" {
+				where = wordListMapLines[name+"'s"]
+			}
+			lines := uniqueStrings(strings.Split(where, ","))
+			if !p.Verbose && len(lines) > 5 {
+				lines = append(lines[:5], "...")
+			}
+			rs = append(rs, fmt.Sprintf("    %s: %s", name, strings.Join(lines, ", ")))
 		}
-		if re_period.MatchString(lcline) {
-			gcreports = append(gcreports, reportln{fmt.Sprintf("unexpected period after word"), fmt.Sprintf("  %5d: %s", n+1, wraptext9(line))})
+		rs = append(rs, "")
+		count++
+	}
+
+	if count == 0 {
+		rs = append(rs, "  no proper name variants found.")
+		rs[0] = "☲" + rs[0] // style dim
+		rs = append(rs, "")
+	} else {
+		rs[0] = "☳" + rs[0] // style black
+	}
+	rs[len(rs)-1] += "☷" // close style
+	tcec += count
+	return rs
+}
+
+// a segment cut from highlighted text with its markers paired: a close
+// marker whose open marker was cut off is dropped, and a highlight cut
+// off at the end is closed
+func balanceMarks(seg string) string {
+	var b strings.Builder
+	open := false
+	for _, c := range seg {
+		switch {
+		case c == '☰':
+			open = true
+		case c == '☷' && !open:
+			continue
+		case c == '☷':
+			open = false
 		}
+		b.WriteRune(c)
+	}
+	if open {
+		b.WriteRune('☷')
 	}
+	return b.String()
+}
 
-	sort.Slice(gcreports, func(i, j int) bool { return gcreports[i].sourceline < gcreports[j].sourceline })
-	sort.SliceStable(gcreports, func(i, j int) bool { return gcreports[i].rpt < gcreports[j].rpt })
+// custom checks
+// the checks in the project's checks.txt. a paragraph match is shown in
+// context and reported at the line where it starts
+func tcCustomChecks(wb []string) []string {
+	rs := []string{}
+	rs = append(rs, "----- custom checks ----------------------------------------------------------")
+	rs = append(rs, "")
 
-	if abandonedTagCount > 10 {
-		rs = append(rs, fmt.Sprintf("note: source file not plain text. %d lines with markup", abandonedTagCount))
+	// paragraphs, with the offset of each of their lines
+	type para struct {
+		text   string
+		lnum   []int
+		offset []int
+	}
+	paras := []para{}
+	for n, line := range wb {
+		if line == "" {
+			continue
+		}
+		if n == 0 || wb[n-1] == "" {
+			paras = append(paras, para{})
+		}
+		pa := &paras[len(paras)-1]
+		if pa.text != "" {
+			pa.text += " "
+		}
+		pa.lnum = append(pa.lnum, n)
+		pa.offset = append(pa.offset, len(pa.text))
+		pa.text += line
 	}
 
-	if len(gcreports) > 0 {
-		rrpt_last := ""
-		ctr := 0 // count this report
-		for _, rpt := range gcreports {
-			if rpt.rpt != rrpt_last {
-				// a report with a new report type
-				// first one gets new header line
-				rs = append(rs, fmt.Sprintf("%s\n%s", rpt.rpt, rpt.sourceline))
-				ctr = 1
-				rrpt_last = rpt.rpt
-				continue
-			} else {
-				// a report with the same report type
-				// handle continuation line
-				s := strings.Replace(rpt.sourceline, "99999: ", "       ", -1)
-				if p.Verbose || ctr < 5 {
-					rs = append(rs, fmt.Sprintf("%s", s)) // subsequent reports
+	// matches not inside an exception, highlighted
+	highlight := func(cc customCheck, s string) (string, int) {
+		locs := [][]int{}
+		var exc [][]int
+		if cc.except != nil {
+			exc = cc.except.FindAllStringIndex(s, -1)
+		}
+		for _, loc := range cc.re.FindAllStringIndex(s, -1) {
+			inside := false
+			for _, e := range exc {
+				inside = inside || loc[0] >= e[0] && loc[1] <= e[1]
+			}
+			if !inside && loc[1] > loc[0] {
+				locs = append(locs, loc)
+			}
+		}
+		if len(locs) == 0 {
+			return "", -1
+		}
+		for i := len(locs) - 1; i >= 0; i-- {
+			a, b := locs[i][0], locs[i][1]
+			s = s[:a] + "☰" + s[a:b] + "☷" + s[b:]
+		}
+		return s, locs[0][0]
+	}
+
+	count := 0
+	for _, cc := range customChecks {
+		reports := []string{}
+		if cc.para {
+			for _, pa := range paras {
+				s, where := highlight(cc, pa.text)
+				if where < 0 {
+					continue
 				}
-				if !p.Verbose && ctr == 5 {
-					rs = append(rs, fmt.Sprintf("         ... more"))
+				n := 0
+				for n+1 < len(pa.offset) && pa.offset[n+1] <= where {
+					n++
 				}
-				ctr += 1
+				reports = append(reports, fmt.Sprintf("  %5d: %s", pa.lnum[n]+1, pt(balanceMarks(getParaSegment(s, where)))))
 			}
+		} else {
+			for n, line := range wb {
+				if s, where := highlight(cc, line); where >= 0 {
+					reports = append(reports, fmt.Sprintf("  %5d: %s", n+1, wraptext9(s)))
+				}
+			}
+		}
+		if len(reports) == 0 {
+			continue
+		}
+		if cc.message != "" {
+			rs = append(rs, fmt.Sprintf("%s: %s", cc.name, cc.message))
+		} else {
+			rs = append(rs, cc.name)
 		}
+		for i, r := range reports {
+			if !p.Verbose && i == 5 {
+				rs = append(rs, fmt.Sprintf("         ... %d more", len(reports)-5))
+				break
+			}
+			rs = append(rs, r)
+		}
+		rs = append(rs, "")
+		count += len(reports)
 	}
 
-	if len(gcreports) == 0 {
-		rs = append(rs, "   no special situation reports.")
+	if count == 0 {
+		if len(customChecks) == 0 {
+			rs = append(rs, "  no custom checks for this project.")
+		} else {
+			rs = append(rs, fmt.Sprintf("  %d custom checks, nothing reported.", len(customChecks)))
+		}
 		rs[0] = "☲" + rs[0] // style dim
+		rs = append(rs, "")
 	} else {
 		rs[0] = "☳" + rs[0] // style black
 	}
-	rs = append(rs, "")
 	rs[len(rs)-1] += "☷" // close style
-	tcec += len(gcreports)
+	tcec += count
 	return rs
 }
 
@@ -3425,19 +6654,29 @@ func textCheck() []string {
 	rs = append(rs, tcAdjacentSpaces(wbuf)...)
 	rs = append(rs, tcTrailingSpaces(wbuf)...)
 	rs = append(rs, tcLetterChecks(wbuf)...)
+	rs = append(rs, tcCharInventory(wbuf)...)
+	rs = append(rs, tcNormalization(wbuf)...)
+	rs = append(rs, tcHomoglyphs(wbuf)...)
 	rs = append(rs, tcSpacingCheck(wbuf)...)
+	rs = append(rs, tcHeaderNumbering()...)
 	rs = append(rs, tcShortLines(wbuf)...)
 	rs = append(rs, tcLongLines(wbuf)...)
 	rs = append(rs, tcRepeatedWords(pbuf)...)
 	rs = append(rs, tcDuplicateLines(wbuf)...)
+	rs = append(rs, tcArtifacts(wbuf)...)
 	rs = append(rs, tcEllipsisCheck(wbuf)...)
 	rs = append(rs, tcDashCheck(wbuf, pbuf)...)
 	rs = append(rs, footnoteCheck(wbuf)...)
 	rs = append(rs, scannoCheck(wbuf)...)
 	rs = append(rs, tcCurlyQuoteCheck(wbuf)...)
 	rs = append(rs, tcGutChecks(wbuf)...)
+	rs = append(rs, tcGreekCheck(wbuf)...)
 	rs = append(rs, tcBookLevel(wbuf)...)
 	rs = append(rs, tcParaLevel()...)
+	rs = append(rs, tcArticles(pbuf)...)
+	rs = append(rs, tcCapitalization(pbuf)...)
+	rs = append(rs, tcNameVariants(pbuf)...)
+	rs = append(rs, tcCustomChecks(wbuf)...)
 
 	if tcec == 0 { // test check error count
 		rs[0] = "☲" + rs[0] // style dim
@@ -3478,14 +6717,16 @@ func getWordList(wb []string) (map[string]int, map[string]string) {
 	ml := make(map[string]string) // map to hold words, lines
 
 	// preserve apostrophes
-	var re1 = regexp.MustCompile(`(\p{L})'(\p{L})`) // letter'letter
-	var re2 = regexp.MustCompile(`(\p{L})’(\p{L})`) // letter’letter
+	var re1 = regexp.MustCompile(`(\p{L})'(\p{L})`)        // letter'letter
+	var re2 = regexp.MustCompile(`(\p{L})’(\p{L})`)        // letter’letter
+	var re3 = regexp.MustCompile(`(^|\P{L})(Mc?)‘(\p{L})`) // M‘Gregor, Mc‘Leod
 	for n, element := range wb {
 		// need this twice to handle alternates i.e. fo’c’s’le or sésame-ouvre-toi
 		element = re1.ReplaceAllString(element, `${1}①${2}`)
 		element = re1.ReplaceAllString(element, `${1}①${2}`)
 		element = re2.ReplaceAllString(element, `${1}②${2}`)
 		element = re2.ReplaceAllString(element, `${1}②${2}`)
+		element = re3.ReplaceAllString(element, `${1}${2}③${3}`)
 
 		// if the user is using "--" as a "—", convert that
 		// temporarily to a space so it can be a word separator
@@ -3499,6 +6740,7 @@ func getWordList(wb []string) (map[string]int, map[string]string) {
 			// put the special characters back in there
 			s := strings.Replace(word, "①", "'", -1)
 			s = strings.Replace(s, "②", "’", -1)
+			s = strings.Replace(s, "③", "‘", -1)
 			// and build the frequency map
 			if _, ok := m[s]; ok { // if it is there already, increment
 				m[s] = m[s] + 1
@@ -3669,6 +6911,10 @@ func levencheck(suspects []string) []string {
 	// for each suspect word, check against all words.
 	// suspects are already sorted, courtesy of aspellCheck()
 	for _, suspect := range suspects {
+		// the good word list can exempt a word from this check alone
+		if inGoodWordList(suspect, "edit") {
+			continue
+		}
 		suspectlc := strings.ToLower(suspect)
 
 		// smoke and mirrors using "e" lookalike
@@ -3765,6 +7011,104 @@ func levencheck(suspects []string) []string {
 var wbs []string // paragraphs as single line, case preserved
 var wbl []string // paragraphs as single line, all lower case
 
+// a jeebies report and how scary it is
+type jeebiesReport struct {
+	tier  int     // words in the form that produced it, 3 or 2
+	scary float64 // how much more common the alternate form is. -1 if this form is unknown
+	text  string
+}
+
+// scary ratio thresholds for three word and two word forms, by paranoia
+// level. the higher the level, the more is reported
+var paranoiaLevels = map[int][2]float64{
+	1: {3.0, 1000.0},
+	2: {1.0, 300.0},
+	3: {0.5, 100.0},
+}
+
+// the forms in the text where the alternate form, with the other word of
+// the pair in place of the word, is more common. three-word forms
+// "w1 word w2" first, then two-word forms " word w2" that are not already
+// reported. the most scary first in each
+func jeebiesPair(cp confusionPair, reported map[string]int) []jeebiesReport {
+	jr := []jeebiesReport{}
+	paranoid_level_3words := paranoiaLevels[p.Paranoia][0]
+	paranoid_level_2words := paranoiaLevels[p.Paranoia][1]
+
+	for _, tier := range []int{3, 2} {
+		for _, pw := range [][]string{{cp.w1, cp.w2}, {cp.w2, cp.w1}} {
+			word, alt := pw[0], pw[1]
+			// "he" forms are reported when the "be" form is at least as common
+			atLeast := word == cp.ties
+			// search for three-word pattern "w1 word w2" or two-word
+			// pattern " word w2" (leading space) in lower-case paragraphs
+			p3b := regexp.MustCompile(`([a-z’]+ ` + regexp.QuoteMeta(word) + ` [a-z’]+)`)
+			paranoid_level := paranoid_level_3words
+			if tier == 2 {
+				p3b = regexp.MustCompile(`( ` + regexp.QuoteMeta(word) + ` [a-z’]+)`)
+				paranoid_level = paranoid_level_2words
+			}
+			for n, para := range wbl {
+				para = " " + para // so a paragraph can start a two-word form
+				for t := p3b.FindStringIndex(para); t != nil; t = p3b.FindStringIndex(para) {
+					// have a form here ("must be taken" or " be taken")
+					sstr := para[t[0]:t[1]]
+					para = strings.Replace(para, sstr, "", 1)
+					w := strings.Split(sstr, " ") // w[0] is "" for two words
+					if tier == 2 && reported[w[1]+" "+w[2]] == 1 {
+						continue // already reported as a three word form
+					}
+					w_count := cp.forms[sstr]
+					// the alternate form ("must he taken") and how common that is
+					alt_count := cp.forms[w[0]+" "+alt+" "+w[2]]
+
+					// if the alternate form exists, based on paranoid_level compared
+					// to this form, report it and include the ratio in favor of the
+					// alternate form. if this form does not exist at all, report it
+					// but do not show any ratio
+					ratio := 0.0
+					if w_count != 0 {
+						ratio = float64(alt_count) / float64(w_count)
+					}
+					if alt_count > 0 && (w_count == 0 || ratio > paranoid_level || atLeast && ratio == paranoid_level) {
+						scary := -1.0
+						if w_count != 0 {
+							scary = ratio
+						}
+						sstr = strings.TrimSpace(sstr)
+						where := strings.Index(strings.ToLower(wbs[n]), sstr)
+						t01 := ""
+						if scary != -1 {
+							t01 = fmt.Sprintf("[%dw] %s (%.1f)\n    %s", tier, sstr, scary, getParaSegment(wbs[n], where))
+						} else {
+							t01 = fmt.Sprintf("[%dw] %s\n    %s", tier, sstr, getParaSegment(wbs[n], where))
+						}
+						if tier == 3 {
+							reported[w[1]+" "+w[2]] = 1
+						}
+						jr = append(jr, jeebiesReport{tier, scary, t01})
+					}
+				}
+			}
+		}
+	}
+
+	// three word forms first. unknown forms are the most scary
+	rank := func(sc float64) float64 {
+		if sc == -1 {
+			return math.Inf(1)
+		}
+		return sc
+	}
+	sort.SliceStable(jr, func(i, j int) bool {
+		if jr[i].tier != jr[j].tier {
+			return jr[i].tier > jr[j].tier
+		}
+		return rank(jr[i].scary) > rank(jr[j].scary)
+	})
+	return jr
+}
+
 func jeebies() []string {
 
 	rs := []string{} // empty rs to start aggregation
@@ -3774,6 +7118,8 @@ func jeebies() []string {
 	rs = append(rs, fmt.Sprintf("* %-76s *", "JEEBIES REPORT"))
 	rs = append(rs, strings.Repeat("*", 80))
 	rs = append(rs, "")
+	rs = append(rs, fmt.Sprintf("paranoia level %d: [3w] three word forms, [2w] two word forms", p.Paranoia))
+	rs = append(rs, "")
 
 	wbuf = append(wbuf, "") // ensure last paragraph converts
 	s := ""
@@ -3791,286 +7137,661 @@ func jeebies() []string {
 		}
 	}
 
-	var scary float64
-	var h_count int
-	var b_count int
-	var reported map[string]int
+	var reported map[string]int
+
+	reported = make(map[string]int)
+	nreports := 0
+
+	nodata := []string{} // pairs declared without frequency data
+	for _, cp := range confusionPairs {
+		if len(cp.forms) == 0 {
+			nodata = append(nodata, cp.w1+"/"+cp.w2)
+			continue
+		}
+		t := jeebiesPair(cp, reported)
+		if len(t) == 0 {
+			continue
+		}
+		rs = append(rs, fmt.Sprintf("%s/%s", cp.w1, cp.w2))
+		for _, jr := range t {
+			rs = append(rs, jr.text)
+		}
+		rs = append(rs, "")
+		nreports += len(t)
+	}
+
+	if nreports == 0 {
+		rs = append(rs, "jeebies found no errors")
+		rs[1] = "☲" + string([]rune(rs[1])[1:]) // switch to dim
+	}
+	if len(nodata) > 0 {
+		rs = append(rs, "")
+		rs = append(rs, "not checked: no frequency data in pairlist.txt (build it with -b)")
+		rs = append(rs, "  "+strings.Join(nodata, ", "))
+	}
+	rs = append(rs, "☷") // and close out dim or black if reports
+	return rs
+}
+
+/* ********************************************************************** */
+/*                                                                        */
+/* word frequency and concordance                                         */
+/*                                                                        */
+/* ********************************************************************** */
+
+// shows the lines of a chosen word in the concordance view. written to
+// concordance.js with the concordance data so the book is not embedded
+// in report.html
+const concScript = `function conc(i) {
+  var w = concWords[i][0];
+  var out = document.getElementById('conc');
+  var seen = {};
+  var re = new RegExp('(^|\\P{L})(' + w.replace(/[.*+?^${}()|[\]\\]/g, '\\$&') + ')(?=\\P{L}|$)', 'gu');
+  out.textContent = w + '\n';
+  concWords[i][1].split(',').forEach(function (n) {
+    if (seen[n]) { return; }
+    seen[n] = true;
+    var line = concLines[n - 1];
+    out.appendChild(document.createTextNode('  ' + ('     ' + n).slice(-5) + ': '));
+    var last = 0, m;
+    while ((m = re.exec(line)) !== null) {
+      var start = m.index + m[1].length;
+      out.appendChild(document.createTextNode(line.slice(last, start)));
+      var sp = document.createElement('span');
+      sp.className = 'red';
+      sp.textContent = m[2];
+      out.appendChild(sp);
+      last = start + m[2].length;
+      re.lastIndex = last;
+    }
+    out.appendChild(document.createTextNode(line.slice(last) + '\n'));
+  });
+}
+`
+
+// every word in the book with how often it occurs and whether it is in
+// the dictionary or the good word list. sorted by count, alphabetically or
+// by reverse spelling (-s). each word links to a concordance view that
+// lists every line it is on
+func wordFreqReport() []string {
+	rs := []string{}
+	rs = append(rs, "☳<a name='words'></a>")
+
+	rs = append(rs, "☳"+strings.Repeat("*", 80))
+	rs = append(rs, fmt.Sprintf("* %-76s *", fmt.Sprintf("WORD FREQUENCY (sorted by %s)", p.WordSort)))
+	rs = append(rs, strings.Repeat("*", 80))
+	rs = append(rs, "")
+
+	words := make([]string, 0, len(wordListMapCount))
+	for w := range wordListMapCount {
+		words = append(words, w)
+	}
+	reverse := func(s string) string {
+		r := []rune(strings.ToLower(s))
+		for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+			r[i], r[j] = r[j], r[i]
+		}
+		return string(r)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		wi, wj := words[i], words[j]
+		switch p.WordSort {
+		case "count":
+			if wordListMapCount[wi] != wordListMapCount[wj] {
+				return wordListMapCount[wi] > wordListMapCount[wj]
+			}
+		case "reverse":
+			if reverse(wi) != reverse(wj) {
+				return reverse(wi) < reverse(wj)
+			}
+		}
+		if strings.ToLower(wi) != strings.ToLower(wj) {
+			return strings.ToLower(wi) < strings.ToLower(wj)
+		}
+		return wi < wj
+	})
+
+	// words aspell does not know in any of the languages. a hyphenated
+	// word is known if all of its parts are
+	aw := make([]string, len(words))
+	for i, w := range words {
+		aw[i] = strings.Replace(w, "’", "'", -1)
+	}
+	for _, rl := range strings.Split(p.Alang, ",") {
+		aw = runAspell(aw, rl)
+	}
+	unknown := make(map[string]bool)
+	for _, w := range aw {
+		unknown[w] = true
+	}
+	inDict := func(w string) bool {
+		for _, part := range strings.Split(strings.Replace(w, "’", "'", -1), "-") {
+			if unknown[part] {
+				return false
+			}
+		}
+		return true
+	}
+
+	// the book and the lines of each word for the concordance, with the
+	// script, in concordance.js next to report.html
+	concWords := make([][]string, len(words))
+	for i, w := range words {
+		concWords[i] = []string{w, wordListMapLines[w]}
+	}
+	jl, _ := json.Marshal(wbuf)
+	jw, _ := json.Marshal(concWords)
+	f2, err := os.Create(p.Outdir + "/concordance.js")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Fprintf(f2, "var concLines = %s;\nvar concWords = %s;\n\n%s", jl, jw, concScript)
+	f2.Close()
+	rs = append(rs, "☳<script src='concordance.js'></script>☷")
+
+	rs = append(rs, "----- concordance -------------------------------------------------------------")
+	rs = append(rs, "")
+	rs = append(rs, "☳<span id='conc'>select a word below to see every line it is on</span>☷")
+	rs = append(rs, "")
+	rs = append(rs, "----- words -------------------------------------------------------------------")
+	rs = append(rs, "")
+	rs = append(rs, fmt.Sprintf("  %6s  %-30s %s", "count", "word", "dictionary / good word"))
+	ndict, ngood, nneither := 0, 0, 0
+	for i, w := range words {
+		status := []string{}
+		if inDict(w) {
+			status = append(status, "dictionary")
+			ndict++
+		}
+		if inGoodWordList(w, "spell") {
+			status = append(status, "good word")
+			ngood++
+		}
+		pad := 30 - utf8.RuneCountInString(w)
+		if pad < 0 {
+			pad = 0
+		}
+		link := fmt.Sprintf("<a href='#words' onclick='conc(%d)'>%s</a>%s", i, w, strings.Repeat(" ", pad))
+		if len(status) == 0 {
+			nneither++
+			rs = append(rs, fmt.Sprintf("☳  %6d  %s ☰neither☷☷", wordListMapCount[w], link))
+		} else {
+			rs = append(rs, fmt.Sprintf("☳  %6d  %s %s☷", wordListMapCount[w], link, strings.Join(status, ", ")))
+		}
+	}
+	rs = append(rs, "")
+	rs = append(rs, fmt.Sprintf("%d words: %d in dictionary, %d good words, %d neither", len(words), ndict, ngood, nneither))
+	rs = append(rs, "☷")
+	return rs
+}
+
+// * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * *
+//
+// scanno list in scannos.txt file. one entry per line:
+//   arid              scanno only
+//   arid→and          scanno and its expected correction ("->" also works)
+//   bad→had @ pattern only when the line matches the regular expression
+// lines starting with "#" are comments
+
+var reScannoArrow *regexp.Regexp = regexp.MustCompile(`\s*(→|->)\s*`)
+
+// a scanno with its word pattern compiled
+func newScanno(word, fix string, ctx *regexp.Regexp) scanno {
+	re := regexp.MustCompile(`(^|\P{L})(` + regexp.QuoteMeta(word) + `)(\P{L}|$)`)
+	return scanno{word, fix, ctx, re}
+}
+
+func readScannos(infile string) []scanno {
+	file, err := os.Open(infile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	swl := []scanno{} // scanno list
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if n == 1 {
+			line = strings.TrimPrefix(line, BOM) // remove BOM if present
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var ctx *regexp.Regexp
+		if t := strings.SplitN(line, " @ ", 2); len(t) == 2 {
+			line = strings.TrimSpace(t[0])
+			if ctx, err = regexp.Compile(strings.TrimSpace(t[1])); err != nil {
+				log.Fatalf("%s line %d: bad context pattern: %v", infile, n, err)
+			}
+		}
+		t := reScannoArrow.Split(line, 2)
+		fix := ""
+		if len(t) == 2 {
+			fix = strings.Replace(t[1], "'", "’", -1)
+		}
+		sc := newScanno(strings.Replace(t[0], "'", "’", -1), fix, ctx)
+		swl = append(swl, sc)
+
+		// title and upper case variants, with the correction to match. their
+		// context ignores case, as "Arid the" starts a sentence
+		if ctx != nil {
+			ctx = regexp.MustCompile("(?i)" + ctx.String())
+		}
+		lc := strings.ToLower(sc.word)
+		if v1 := strings.Title(lc); v1 != sc.word {
+			swl = append(swl, newScanno(v1, strings.Title(sc.fix), ctx))
+		}
+		if v1 := strings.ToUpper(lc); v1 != sc.word {
+			swl = append(swl, newScanno(v1, strings.ToUpper(sc.fix), ctx))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+	return swl
+}
+
+// consistency groups in consistency.txt. one group per line, the
+// variants separated by "|":
+//   today | to-day          words or phrases, matched ignoring case
+//   *ise | *ize             word endings, compared stem by stem
+//   /(?i)\bgr[ae]y\b/ | ... regular expressions
+// lines starting with "#" are comments
+
+func readConsistency(infile string) []consistencyGroup {
+	file, err := os.Open(infile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	cgs := []consistencyGroup{}
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if n == 1 {
+			line = strings.TrimPrefix(line, BOM) // remove BOM if present
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		cg := consistencyGroup{}
+		names := splitVariants(line)
+		if len(names) < 2 {
+			log.Fatalf("%s line %d: a group needs two or more variants", infile, n)
+		}
+		for _, name := range names {
+			switch {
+			case name == "":
+				log.Fatalf("%s line %d: empty variant", infile, n)
+			case strings.HasPrefix(name, "*"):
+				cg.suffixes = append(cg.suffixes, strings.ToLower(name[1:]))
+			case len(name) > 1 && strings.HasPrefix(name, "/") && strings.HasSuffix(name, "/"):
+				re, err := regexp.Compile(name[1 : len(name)-1])
+				if err != nil {
+					log.Fatalf("%s line %d: bad pattern: %v", infile, n, err)
+				}
+				cg.variants = append(cg.variants, variant{name[1 : len(name)-1], re, false})
+			default:
+				cg.variants = append(cg.variants, literalVariant(name))
+			}
+		}
+		if len(cg.suffixes) > 0 && len(cg.variants) > 0 {
+			log.Fatalf("%s line %d: suffixes cannot be grouped with other variants", infile, n)
+		}
+		// longer variants first, see variantsOn
+		sort.SliceStable(cg.variants, func(i, j int) bool { return len(cg.variants[i].name) > len(cg.variants[j].name) })
+		cgs = append(cgs, cg)
+	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+	return cgs
+}
+
+// split a consistency group into its variants. a "|" inside a /regex/
+// does not separate variants
+func splitVariants(line string) []string {
+	vs := []string{}
+	cur := ""
+	inRe := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && inRe && i+1 < len(line):
+			cur += line[i : i+2]
+			i++
+		case c == '/' && strings.TrimSpace(cur) == "":
+			inRe = true
+			cur += "/"
+		case c == '/' && inRe:
+			inRe = false
+			cur += "/"
+		case c == '|' && !inRe:
+			vs = append(vs, strings.TrimSpace(cur))
+			cur = ""
+		default:
+			cur += line[i : i+1]
+		}
+	}
+	return append(vs, strings.TrimSpace(cur))
+}
 
-	reported = make(map[string]int)
-	nreports := 0
+// a word or phrase variant. spaces in it match any run of spaces
+func literalVariant(name string) variant {
+	pat := strings.Replace(regexp.QuoteMeta(name), " ", `\s+`, -1)
+	return variant{name, regexp.MustCompile(`(?i)` + pat), true}
+}
 
-	// looking for "be" errors
-	// search for three-word pattern  "w1 be w2" in lower-case paragraphs
-	paranoid_level_3words := 1.0
-	p3b := regexp.MustCompile(`([a-z’]+ be [a-z’]+)`)
-	for n, para := range wbl {
-		t := p3b.FindStringIndex(para)
-		if t != nil {
-			for ok := true; ok; ok = (t != nil) {
-				// have a match
-				sstr := (para[t[0]:t[1]])
-				para = strings.Replace(para, sstr, "", 1)
-				// have a three word form here ("must be taken")
-				// see if it is in the beMap map
-				b_count = 0
-				if val, ok := beMap[sstr]; ok {
-					b_count = val
-				}
-				// change "be" to "he" and see if that is in the heMap map
-				sstr2 := strings.Replace(sstr, "be", "he", 1)
-				h_count = 0
-				if val, ok := heMap[sstr2]; ok {
-					h_count = val
-				}
-				// here I have the "be" form and how common that is in b_count
-				// and the "he" form and how common that is in h_count
-				// fmt.Printf("%d %s\n%d %s\n\n", b_count, sstr, h_count, sstr2)
-				if h_count > 0 && (b_count == 0 || float64(h_count)/float64(b_count) > paranoid_level_3words) {
-					// calculate how scary it is.
-					// if the "he" form is three times more likely than the "be" form,
-					// then scary calculates to 3.0
-					if b_count == 0 {
-						scary = -1.0
-					} else {
-						scary = float64(h_count) / float64(b_count)
-					}
-					where := strings.Index(strings.ToLower(wbs[n]), strings.ToLower(sstr))
-					t01 := ""
-					if scary != -1 {
-						t01 = fmt.Sprintf("%s (%.1f)\n    %s", sstr, scary, getParaSegment(wbs[n], where))
-					} else {
-						t01 = fmt.Sprintf("%s\n    %s", sstr, getParaSegment(wbs[n], where))
-					}
-					reported[strings.SplitAfterN(sstr, " ", 2)[1]] = 1
-					rs = append(rs, t01)
-					nreports++
-				}
+// project checks in checks.txt. one check per group of lines, the
+// groups separated by blank lines:
+//   name: Jones OCR'd as Janes      required
+//   match: \bJanes\b               required, a regular expression
+//   scope: line                     line (the default) or paragraph
+//   except: Mrs\. Janes             matches inside this are not reported
+//   message: the name is Jones
+// lines starting with "#" are comments
 
-				// see if there is another candidate
-				t = p3b.FindStringIndex(para)
+func readCustomChecks(infile string) []customCheck {
+	file, err := os.Open(infile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+	ccs := []customCheck{}
+	cc := customCheck{}
+	start := 0 // line the check in progress starts on
+	finish := func() {
+		if start == 0 {
+			return
+		}
+		if cc.name == "" || cc.re == nil {
+			log.Fatalf("%s line %d: a check needs a name and a match", infile, start)
+		}
+		ccs = append(ccs, cc)
+		cc, start = customCheck{}, 0
+	}
+	scanner := bufio.NewScanner(file)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSuffix(scanner.Text(), "\r") // CRLF line endings
+		if n == 1 {
+			line = strings.TrimPrefix(line, BOM) // remove BOM if present
+		}
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		if strings.TrimSpace(line) == "" {
+			finish()
+			continue
+		}
+		if start == 0 {
+			start = n
+		}
+		t := strings.SplitN(line, ":", 2)
+		if len(t) != 2 {
+			log.Fatalf("%s line %d: expected key: value", infile, n)
+		}
+		key, value := strings.TrimSpace(t[0]), strings.TrimSpace(t[1])
+		switch key {
+		case "name":
+			cc.name = value
+		case "match", "except":
+			// a pattern is kept as written after the one space following
+			// the colon, so it can start or end with a space
+			re, err := regexp.Compile(strings.TrimPrefix(t[1], " "))
+			if err != nil {
+				log.Fatalf("%s line %d: bad pattern: %v", infile, n, err)
 			}
-
+			if key == "match" {
+				cc.re = re
+			} else {
+				cc.except = re
+			}
+		case "scope":
+			if value != "line" && value != "paragraph" {
+				log.Fatalf("%s line %d: scope must be line or paragraph", infile, n)
+			}
+			cc.para = value == "paragraph"
+		case "message":
+			cc.message = value
+		default:
+			log.Fatalf("%s line %d: unknown key %s", infile, n, key)
 		}
 	}
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+	finish()
+	return ccs
+}
 
-	// looking for "he" errors
-	// search for three-word pattern  "w1 he w2" in lower-case paragraphs
-	p3b = regexp.MustCompile(`([a-z’]+ he [a-z’]+)`)
-	for n, para := range wbl {
-		t := p3b.FindStringIndex(para)
-		if t != nil {
-			for ok := true; ok; ok = (t != nil) {
-				// have a match
-				sstr := (para[t[0]:t[1]])
-				para = strings.Replace(para, sstr, "", 1)
-				// have a three word form here ("must he taken")
-				// see if it is in the heMap map
-				h_count = 0
-				if val, ok := heMap[sstr]; ok {
-					h_count = val
-				}
-				// change "he" to "be" and see if that is in the beMap map
-				sstr2 := strings.Replace(sstr, "he", "be", 1)
-				b_count = 0
-				if val, ok := beMap[sstr2]; ok {
-					b_count = val
-				}
-				// here I have the "he" form and how common that is in h_count
-				// and the "be" form and how common that is in b_count
-				// fmt.Printf("%d %s\n%d %s\n\n", h_count, sstr, b_count, sstr2)
-				// if the alternate ("be") form exists, based on paranoid_level
-				// compared to the "he" form, report it and include the ratio in favor
-				// of the alternate "be" form.
-				// if the alternate ("be") form exists and the "he" form does not exist,
-				// report it but do not show any ratio
-				if b_count > 0 && (h_count == 0 || float64(b_count)/float64(h_count) >= paranoid_level_3words) {
-					// calculate how scary it is.
-					if h_count == 0 {
-						scary = -1.0
-					} else {
-						scary = float64(b_count) / float64(h_count)
-					}
-					where := strings.Index(strings.ToLower(wbs[n]), strings.ToLower(sstr))
-					t01 := ""
-					if scary != -1 {
-						t01 = fmt.Sprintf("%s (%.1f)\n    %s", sstr, scary, getParaSegment(wbs[n], where))
-					} else {
-						t01 = fmt.Sprintf("%s\n    %s", sstr, getParaSegment(wbs[n], where))
-					}
-					reported[strings.SplitAfterN(sstr, " ", 2)[1]] = 1
-					rs = append(rs, t01)
-					nreports++
-				}
-
-				// see if there is another candidate
-				t = p3b.FindStringIndex(para)
+// add scannos to the list. an entry for a word already in the list
+// replaces it, so a project scanno file can override the shared one
+func addScannos(swl []scanno) {
+	for _, sc := range swl {
+		found := false
+		for i := range scannoList {
+			if scannoList[i].word == sc.word {
+				scannoList[i] = sc
+				found = true
 			}
 		}
+		if !found {
+			scannoList = append(scannoList, sc)
+		}
 	}
+}
 
-	// prettyPrint(reported)
-
-	/*
-	   // check two word forms.
-	   // ignore any that have been caught with three word forms by checking 'reported' map
-
-	   paranoid_level_2words := 300.0
-
-	   // looking for "be" errors
-	   // search for two-word pattern  " be word2" in lower-case paragraphs
-	   // "Please be happy for me."
-	   p3b = regexp.MustCompile(`( be [a-z']+)`) // leading space
-	   for n, para := range wbl {
-	       t := p3b.FindStringIndex(para)
-	       skipreport := false
-	       if t != nil { // found a " be word2" form
-	           for ok := true; ok; ok = (t != nil) {
-	               // have a match
-	               sstr := (para[t[0]:t[1]])            // " be happy" with leading space
-	               if _, ok := reported[sstr[1:]]; ok { // already reported?
-	                   skipreport = true
-	                   // fmt.Println(sstr[1:] + " already reported")
-	               }
-	               para = strings.Replace(para, sstr, "", 1) // remove this one before scan for another
-	               // have a two word form here ("be happy")
-	               // see if it is in the beMap list
-	               b_count = 0
-	               if val, ok := beMap[sstr]; ok { // searches the "beMap" map
-	                   b_count = val
-	               }
-	               // change "be" to "he" and see if that is in the heMap list
-	               sstr2 := strings.Replace(sstr, "be", "he", 1) // " he happy"
-	               h_count = 0
-	               if val, ok := heMap[sstr2]; ok {
-	                   h_count = val
-	               }
-	               // here I have the "be" form and how common that is in b_count
-	               // and the "he" form and how common that is in h_count
-	               // fmt.Printf("%d %s\n%d %s\n\n", b_count, sstr, h_count, sstr2)
-	               if h_count > 0 && (b_count == 0 || float64(h_count)/float64(b_count) > paranoid_level_2words) {
-	                   // calculate how scary it is.
-	                   // if the "he" form is three times more likely than the "be" form,
-	                   // then scary calculates to 3.0
-	                   if b_count == 0 {
-	                       scary = -1.0
-	                   } else {
-	                       scary = float64(h_count) / float64(b_count)
-	                   }
-	                   where := strings.Index(strings.ToLower(wbs[n]), strings.ToLower(sstr))
-	                   t01 := ""
-	                   if scary != -1 {
-	                       t01 = fmt.Sprintf("%s (%.1f)\n    %s", strings.TrimSpace(sstr), scary, getParaSegment(wbs[n], where))
-	                   } else {
-	                       t01 = fmt.Sprintf("%s\n    %s", strings.TrimSpace(sstr), getParaSegment(wbs[n], where))
-	                   }
-	                   if !skipreport {
-	                       rs = append(rs, t01)
-	                   }
-	               }
-
-	               // see if there is another candidate
-	               t = p3b.FindStringIndex(para)
-	           }
-	       }
-	   }
-
-	   // looking for "he" errors
-	   // search for two-word pattern  " he word2" in lower-case paragraphs
-	   p3b = regexp.MustCompile(`( he [a-z']+)`) // leading space
-	   for n, para := range wbl {
-	       t := p3b.FindStringIndex(para)
-	       skipreport := false
-	       if t != nil {
-	           for ok := true; ok; ok = (t != nil) {
-	               sstr := (para[t[0]:t[1]])
-	               if _, ok := reported[sstr[1:]]; ok {
-	                   skipreport = true
-	                   // fmt.Println(sstr[1:] + " already reported")
-	               }
-	               para = strings.Replace(para, sstr, "", 1)
-	               h_count = 0
-	               if val, ok := heMap[sstr]; ok {
-	                   h_count = val
-	               }
-	               // change "he" to "be" and see if that is in the beMap list
-	               sstr2 := strings.Replace(sstr, "he", "be", 1)
-	               b_count = 0
-	               if val, ok := heMap[sstr2]; ok {
-	                   b_count = val
-	               }
-	               // here I have the "be" form and how common that is in b_count
-	               // and the "he" form and how common that is in h_count
-	               // fmt.Printf("%d %s\n%d %s\n\n", b_count, sstr, h_count, sstr2)
-	               if b_count > 0 && (h_count == 0 || float64(b_count)/float64(h_count) > paranoid_level_2words) {
-	                   if h_count == 0 {
-	                       scary = -1.0
-	                   } else {
-	                       scary = float64(b_count) / float64(h_count)
-	                   }
-	                   where := strings.Index(strings.ToLower(wbs[n]), strings.ToLower(sstr))
-	                   t01 := ""
-	                   if scary != -1 {
-	                       t01 = fmt.Sprintf("%s (%.1f)\n    %s", strings.TrimSpace(sstr), scary, getParaSegment(wbs[n], where))
-	                   } else {
-	                       t01 = fmt.Sprintf("%s\n    %s", strings.TrimSpace(sstr), getParaSegment(wbs[n], where))
-	                   }
-	                   if !skipreport {
-	                       rs = append(rs, t01)
-	                   }
-	               }
-
-	               // see if there is another candidate
-	               t = p3b.FindStringIndex(para)
-	           }
-	       }
-	   }
-	*/
-
-	if nreports == 0 {
-		rs = append(rs, "jeebies found no errors")
-		rs[1] = "☲" + string([]rune(rs[1])[1:]) // switch to dim
+// replace the scanno in the parts of a line its context pattern matches.
+// scannos without a correction or context are never replaced
+func fixScanno(line string, sc scanno) string {
+	if sc.fix == "" || sc.ctx == nil {
+		return line
 	}
-	rs = append(rs, "☷") // and close out dim or black if reports
-	return rs
+	return sc.ctx.ReplaceAllStringFunc(line, func(m string) string {
+		return sc.re.ReplaceAllString(m, "${1}"+sc.fix+"${3}")
+	})
 }
 
 // * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * *
 //
-// scanno word list in in scannos.txt file
+// he word list and be word list in in patterns.txt
+// bracketed by *** BEGIN HE *** and *** END HE ***
+// hebelist.txt is all lower case; contains many ’ apostrophes
+
+// confusion pairs in pairlist.txt. one section per pair:
+//   *** BEGIN PAIR had/bad ***
+//   i|had|been:1200
+//   |had|been:3400
+//   *** END PAIR ***
+// a form is "w1|word|w2:count" where word is either word of the pair.
+// a line "ties:word" reports the forms of that word also when the other
+// word is exactly as much more common as the paranoia level asks.
+// a section without forms declares a pair with no data yet. a pair may
+// be written in either order. lines outside the sections are ignored
+
+var rePairBegin *regexp.Regexp = regexp.MustCompile(`^\*\*\* BEGIN PAIR (\S+)/(\S+) \*\*\*$`)
+
+func readConfusionPairs(infile string) []confusionPair {
+	cps := []confusionPair{}
 
-func readScannos(infile string) []string {
 	file, err := os.Open(infile)
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer file.Close()
-	swl := []string{} // scanno word list
+
 	scanner := bufio.NewScanner(file)
+	inpair := false
 	for scanner.Scan() {
-		scoword := scanner.Text()
-		swl = append(swl, scoword)
-		v1 := strings.Title(strings.ToLower(scoword))
-		if v1 != scoword {
-			swl = append(swl, v1)
+		line := strings.TrimPrefix(scanner.Text(), BOM)
+		if t := rePairBegin.FindStringSubmatch(line); t != nil {
+			cps = append(cps, confusionPair{t[1], t[2], make(map[string]int), ""})
+			inpair = true
+			continue
+		}
+		if line == "*** END PAIR ***" {
+			inpair = false
+			continue
 		}
-		v1 = strings.ToUpper(strings.ToLower(scoword))
-		if v1 != scoword {
-			swl = append(swl, v1)
+		if inpair {
+			t := strings.Split(line, ":")
+			if len(t) != 2 {
+				continue
+			}
+			if t[0] == "ties" {
+				cps[len(cps)-1].ties = t[1]
+				continue
+			}
+			ttmp := strings.Replace(t[0], "|", " ", -1)
+			n, _ := strconv.Atoi(t[1])
+			cps[len(cps)-1].forms[ttmp] = n
 		}
 	}
 	if err := scanner.Err(); err != nil {
 		log.Fatal(err)
 	}
-	// remove BOM if present
-	swl[0] = strings.TrimPrefix(swl[0], BOM)
-	return swl
+	return cps
 }
 
-// * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * * *
-//
-// he word list and be word list in in patterns.txt
-// bracketed by *** BEGIN HE *** and *** END HE ***
-// hebelist.txt is all lower case; contains many ’ apostrophes
+// add confusion pairs to a list. a pair already in the list gets the
+// forms of the new one, if it has any, so pairlist.txt can replace the
+// he/be forms of hebelist.txt
+func mergePairs(cps []confusionPair, add []confusionPair) []confusionPair {
+	for _, cp := range add {
+		found := false
+		for i := range cps {
+			if cps[i].w1 == cp.w1 && cps[i].w2 == cp.w2 || cps[i].w1 == cp.w2 && cps[i].w2 == cp.w1 {
+				if len(cp.forms) > 0 {
+					cps[i].forms = cp.forms
+				}
+				if cp.ties != "" {
+					cps[i].ties = cp.ties
+				}
+				found = true
+			}
+		}
+		if !found {
+			cps = append(cps, cp)
+		}
+	}
+	return cps
+}
+
+var reCorpusWord *regexp.Regexp = regexp.MustCompile(`[\p{L}’]+`)
+var reCorpusContext *regexp.Regexp = regexp.MustCompile(`^[a-z’]+$`)
+
+// count the two word (|word|w2) and three word (w1|word|w2) forms around
+// each target word in a book. the words of a form are separated by single
+// spaces, as jeebies finds them in the text
+func countContexts(lines []string, target map[string]bool, counts map[string]int) {
+	paras := []string{}
+	s := ""
+	for _, line := range append(lines, "") {
+		if strings.TrimSpace(line) == "" {
+			if s != "" {
+				paras = append(paras, strings.TrimSpace(s))
+				s = ""
+			}
+		} else {
+			s = s + " " + line
+		}
+	}
+	for _, para := range paras {
+		para = strings.Replace(strings.ToLower(para), "'", "’", -1)
+		locs := reCorpusWord.FindAllStringIndex(para, -1)
+		// word n as a context word next to word m, or "" if it cannot be
+		word := func(n, m int) string {
+			if n < 0 || n >= len(locs) {
+				return ""
+			}
+			lo, hi := locs[n], locs[m]
+			if n > m {
+				lo, hi = locs[m], locs[n]
+			}
+			w := para[locs[n][0]:locs[n][1]]
+			if para[lo[1]:hi[0]] != " " || !reCorpusContext.MatchString(w) {
+				return ""
+			}
+			return w
+		}
+		for n, loc := range locs {
+			w := para[loc[0]:loc[1]]
+			if !target[w] {
+				continue
+			}
+			after := word(n+1, n)
+			if after == "" {
+				continue
+			}
+			counts["|"+w+"|"+after]++
+			if before := word(n-1, n); before != "" {
+				counts[before+"|"+w+"|"+after]++
+			}
+		}
+	}
+}
+
+// build pairlist.txt in the output directory from the plain text books
+// (*.txt) in corpusdir and its subdirectories. forms seen fewer than
+// mincount times are left out. returns a summary for the user
+func buildPairList(corpusdir string, cps []confusionPair, mincount int) []string {
+	target := make(map[string]bool)
+	for _, cp := range cps {
+		target[cp.w1] = true
+		target[cp.w2] = true
+	}
+
+	counts := make(map[string]int)
+	nbooks := 0
+	err := filepath.Walk(corpusdir, func(fpath string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.ToLower(filepath.Ext(fpath)) != ".txt" {
+			return nil
+		}
+		countContexts(readText(fpath), target, counts)
+		nbooks++
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	f2, err := os.Create(p.Outdir + "/pairlist.txt")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f2.Close()
+	fmt.Fprintf(f2, "confusion pairs for jeebies. one section per pair with the frequency of\n")
+	fmt.Fprintf(f2, "three word (w1|word|w2) and two word (|word|w2) forms containing either\n")
+	fmt.Fprintf(f2, "word. built from %d books in %s, minimum count %d.\n\n", nbooks, corpusdir, mincount)
+
+	rs := []string{fmt.Sprintf("books read: %d", nbooks)}
+	for _, cp := range cps {
+		forms := []string{}
+		for k, v := range counts {
+			w := strings.Split(k, "|")[1]
+			if (w == cp.w1 || w == cp.w2) && v >= mincount {
+				forms = append(forms, k)
+			}
+		}
+		sort.Strings(forms)
+		fmt.Fprintf(f2, "*** BEGIN PAIR %s/%s ***\n", cp.w1, cp.w2)
+		if cp.ties != "" {
+			fmt.Fprintf(f2, "ties:%s\n", cp.ties)
+		}
+		for _, k := range forms {
+			fmt.Fprintf(f2, "%s:%d\n", k, counts[k])
+		}
+		fmt.Fprintf(f2, "*** END PAIR ***\n")
+		rs = append(rs, fmt.Sprintf("  %s/%s: %d forms", cp.w1, cp.w2, len(forms)))
+	}
+	rs = append(rs, "pair tables saved in pairlist.txt")
+	return rs
+}
 
 func readHeBe(infile string) (map[string]int, map[string]int) {
 	hmp := make(map[string]int)
@@ -4122,52 +7843,210 @@ func readHeBe(infile string) (map[string]int, map[string]int) {
 }
 
 // read in the chosen word list (good word list)
-// convert any straight quote marks to apostrophes
-// if lower case, add title and upper case
-// if title case, add upper case
+// one entry per line:
+//   word         whole word, with case variants (see caseVariants)
+//   =Word        case-exact word, no case variants
+//   /pattern/    regular expression matching whole words
+//   spell:word   entry used by one check only: spell, scanno or edit.
+//                the scope goes before the other forms, as in edit:=Word
+// "#" starts a comment, at the start of a line or after a space.
+// convert any straight quote marks to apostrophes in words
+
+var reGWComment *regexp.Regexp = regexp.MustCompile(`(^|\s)#.*$`)
+var reGWScope *regexp.Regexp = regexp.MustCompile(`^(spell|scanno|edit):`)
+
+// a good word pattern matched as whole words. an apostrophe between
+// letters is part of the word, so "o" does not match "o’er", but a
+// possessive "’s" may follow. the boundaries are the named groups gwpre
+// and gwpost, apart from any groups in the pattern itself
+func goodWordRegexp(pat string) (*regexp.Regexp, error) {
+	return regexp.Compile(`(?P<gwpre>^|[^\p{L}’']|(?:^|[^\p{L}])[’'])(?:` + pat +
+		`)(?P<gwpost>$|[^\p{L}’']|[’']s?(?:$|[^\p{L}]))`)
+}
 
-func readWordList(infile string) ([]string, int) {
-	wd := []string{}
+func readWordList(infile string) ([]goodWord, int) {
+	wd := []goodWord{}
 	file, err := os.Open(infile) // try to open wordlist
 	if err != nil {
 		return wd, 0 // early exit if it isn't present
 	}
 	defer file.Close() // here if it opened
 	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		// skip blank lines
-		if scanner.Text() != "" {
-			wd = append(wd, scanner.Text())
+	goodwordsread := 0 // how many user provided (before I augment the list)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if n == 1 {
+			line = strings.TrimPrefix(line, BOM) // remove BOM if present
+		}
+		line = strings.TrimSpace(reGWComment.ReplaceAllString(line, ""))
+		// skip blank lines and comments
+		if line == "" {
+			continue
+		}
+		goodwordsread++
+
+		scope := ""
+		if t := reGWScope.FindStringSubmatch(line); t != nil {
+			scope = t[1]
+			line = strings.TrimPrefix(line, t[0])
+		}
+
+		// regular expression entry
+		if len(line) > 2 && strings.HasPrefix(line, "/") && strings.HasSuffix(line, "/") {
+			pat := line[1 : len(line)-1]
+			re, err := goodWordRegexp(pat)
+			if err != nil {
+				log.Fatalf("%s line %d: bad pattern %s: %v", infile, n, line, err)
+			}
+			wd = append(wd, goodWord{pat, re, scope, true})
+			continue
+		}
+
+		line = strings.Replace(line, "'", "’", -1) // "'" to apostrophes
+		words := []string{}
+		if strings.HasPrefix(line, "=") { // case-exact
+			words = append(words, strings.TrimPrefix(line, "="))
+		} else {
+			words = append(words, line)
+			words = append(words, caseVariants(line)...)
+		}
+		for _, word := range words {
+			re, _ := goodWordRegexp(regexp.QuoteMeta(word))
+			wd = append(wd, goodWord{word, re, scope, false})
 		}
 	}
 	if err := scanner.Err(); err != nil {
 		log.Fatal(err)
 	}
-	// remove BOM if present
-	if len(wd) > 0 {
-		wd[0] = strings.TrimPrefix(wd[0], BOM) // on first word if there is one
+	return wd, goodwordsread
+}
+
+// case variants of a good word that the good word list also accepts
+// if lower case, add title and upper case
+// if title case, add upper case
+func caseVariants(word string) []string {
+	v := []string{}
+	if strings.ToLower(word) == word { // all lower case
+		v = append(v, strings.Title(word))   // title case word
+		v = append(v, strings.ToUpper(word)) // upper case word
+	}
+	if strings.Title(word) == word { // title case
+		v = append(v, strings.ToUpper(word)) // upper case word
+	}
+	return v
+}
+
+// write the remaining spellcheck suspects to good_words_candidates.txt,
+// one per line with how often it occurs and the first line it is on.
+// the user deletes the lines that are not good words and merges the
+// rest into the good words file with -m
+func writeCandidates(suspects []string) {
+	f2, err := os.Create(p.Outdir + "/good_words_candidates.txt")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f2.Close()
+	for _, word := range suspects {
+		first := strings.Split(wordListMapLines[word], ",")[0]
+		if first == "" {
+			first = "-" // hyphenated word, not in the line map
+		}
+		fmt.Fprintf(f2, "%s\t%d\t%s\n", word, wordListMapCount[word], first)
 	}
-	// "'" to apostrophes
-	for i, word := range wd {
-		wd[i] = strings.Replace(word, "'", "’", -1)
+}
+
+// merge approved words (the first field of each line of infile, as
+// written by writeCandidates) into the good words file. the file is kept
+// as it is; the new words are added at the end, sorted. a word is left
+// out if an entry of the file already accepts it, or if it is a case
+// variant of another new word
+func mergeGoodWords(infile string, gwfile string) []string {
+	approved := readText(infile)
+	if len(approved) == 0 {
+		log.Fatalf("no approved words in %s", infile)
+	}
+	existing, nexisting := readWordList(gwfile)
+
+	// an entry of the file that applies to the spellcheck accepts the word
+	accepted := func(w string) bool {
+		for _, gw := range existing {
+			if gw.scope != "" && gw.scope != "spell" {
+				continue
+			}
+			if gw.regex {
+				if ok, _ := regexp.MatchString(`^(?:`+gw.word+`)$`, w); ok {
+					return true
+				}
+			} else if gw.word == w {
+				return true
+			}
+		}
+		return false
 	}
 
-	goodwordsread := len(wd) // how many user provided (before I augment the list)
+	norm := func(w string) string { return strings.Replace(w, "'", "’", -1) }
+	entries := map[string]string{} // key -> word as written
+	dropped := 0
+	for _, line := range approved {
+		f := strings.Fields(line)
+		if len(f) == 0 {
+			continue
+		}
+		if _, ok := entries[norm(f[0])]; ok {
+			continue
+		}
+		if accepted(norm(f[0])) {
+			dropped++
+			continue
+		}
+		entries[norm(f[0])] = f[0]
+	}
 
-	// if lower case, add title and upper case
-	// if title case, add upper case
-	addwd := []string{}
-	for _, word := range wd {
-		if strings.ToLower(word) == word { // all lower case
-			addwd = append(addwd, strings.Title(word))   // title case word
-			addwd = append(addwd, strings.ToUpper(word)) // upper case word
+	// leave out entries covered by the case variants of another entry
+	covered := map[string]bool{}
+	for key := range entries {
+		for _, v := range caseVariants(key) {
+			covered[v] = true
 		}
-		if strings.Title(word) == word { // title case
-			addwd = append(addwd, strings.ToUpper(word)) // upper case word
+	}
+	words := []string{}
+	for key, w := range entries {
+		if covered[key] {
+			dropped++
+			continue
 		}
+		words = append(words, w)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		li, lj := strings.ToLower(words[i]), strings.ToLower(words[j])
+		if li != lj {
+			return li < lj
+		}
+		return words[i] < words[j]
+	})
+
+	// append, starting on a line of its own
+	sep := ""
+	if raw, err := os.ReadFile(gwfile); err == nil && len(raw) > 0 && raw[len(raw)-1] != '\n' {
+		sep = "\n"
+	}
+	f2, err := os.OpenFile(gwfile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f2.Close()
+	if len(words) > 0 {
+		fmt.Fprint(f2, sep)
+	}
+	for _, w := range words {
+		fmt.Fprintf(f2, "%s\n", w)
+	}
+	return []string{
+		fmt.Sprintf("good words file: %s", gwfile),
+		fmt.Sprintf("words added: %d", len(words)),
+		fmt.Sprintf("left out (already accepted or case variants): %d", dropped),
+		fmt.Sprintf("entries in file: %d", nexisting+len(words)),
 	}
-	wd = append(wd, addwd...)
-	return wd, goodwordsread
 }
 
 // runs aspell on a slice of strings and returns a unique set of those that
@@ -4225,6 +8104,18 @@ func doparams() params {
 	flag.BoolVar(&p.Verbose, "v", false, "Verbose operation")
 	flag.BoolVar(&p.Revision, "r", false, "return Revision number")
 	flag.BoolVar(&p.Debug, "d", false, "Debug flag")
+	flag.BoolVar(&p.Curl, "c", false, "curl quotes: write converted copy of text")
+	flag.StringVar(&p.Fixes, "f", "", "fixes to apply: trailing,spaces,bom,dash,etc,punct,scannos or all")
+	flag.BoolVar(&p.Candidates, "w", false, "write suspect words to good_words_candidates.txt")
+	flag.StringVar(&p.MergeFile, "m", "", "merge approved words file into good words file (-g)")
+	flag.StringVar(&p.CorpusDir, "b", "", "build pairlist.txt from the books in this directory")
+	flag.IntVar(&p.MinCount, "n", 2, "minimum count of a form kept when building pairlist.txt")
+	flag.IntVar(&p.Paranoia, "p", 2, "jeebies paranoia level: 1 (fewest reports) to 3 (most)")
+	flag.StringVar(&p.WordSort, "s", "count", "word frequency order: count, alpha or reverse")
+	flag.StringVar(&p.Profile, "u", "", "allowed characters: ascii, latin1, latin1-curly or a profile file")
+	flag.BoolVar(&p.Normalize, "z", false, "normalize the text to NFC before the checks")
+	flag.StringVar(&p.Articles, "l", "modern", "a/an usage: modern, or period to allow \"an hotel\"")
+	flag.StringVar(&p.Dashes, "e", "auto", "dash convention: auto, unicode or ascii (\"--\" for em-dash)")
 	flag.Parse()
 	return p
 }
@@ -4251,6 +8142,45 @@ func main() {
 		return
 	}
 
+	if _, ok := paranoiaLevels[p.Paranoia]; !ok {
+		log.Fatalf("paranoia level must be 1, 2 or 3")
+	}
+	if p.WordSort != "count" && p.WordSort != "alpha" && p.WordSort != "reverse" {
+		log.Fatalf("word frequency order must be count, alpha or reverse")
+	}
+	if p.Dashes != "auto" && p.Dashes != "unicode" && p.Dashes != "ascii" {
+		log.Fatalf("dash convention must be auto, unicode or ascii")
+	}
+	if p.Articles != "modern" && p.Articles != "period" {
+		log.Fatalf("a/an usage must be modern or period")
+	}
+
+	// merging approved words into the good words file runs by itself
+	if p.MergeFile != "" {
+		if p.GWFilename == "" {
+			log.Fatal("No good words file specified (-g)")
+		}
+		for _, s := range mergeGoodWords(p.MergeFile, p.GWFilename) {
+			fmt.Println(s)
+		}
+		return
+	}
+
+	// building the confusion pair tables from a corpus runs by itself.
+	// the pairs are he/be and those declared in pairlist.txt
+	if p.CorpusDir != "" {
+		execut, _ := os.Executable()
+		cps := []confusionPair{{"he", "be", nil, ""}}
+		pfile := filepath.Join(filepath.Dir(execut), "pairlist.txt")
+		if _, err := os.Stat(pfile); err == nil {
+			cps = mergePairs(cps, readConfusionPairs(pfile))
+		}
+		for _, s := range buildPairList(p.CorpusDir, cps, p.MinCount) {
+			fmt.Println(s)
+		}
+		return
+	}
+
 	if p.Infile == "" {
 		log.Fatal("No input file specified")
 	}
@@ -4286,11 +8216,82 @@ func main() {
 	}
 	pptr = append(pptr, fmt.Sprintf("verbose mode: %s", onoff))
 
-	// load scannos from data file
-	scannoWordlist = readScannos(filepath.Join(loc_exec, "scannos.txt"))
+	// load scannos from data file, then any for the languages in use and
+	// any in the project (the directory of the input file)
+	scannoFiles := []string{filepath.Join(loc_exec, "scannos.txt")}
+	for _, lang := range strings.Split(p.Alang, ",") {
+		scannoFiles = append(scannoFiles, filepath.Join(loc_exec, "scannos-"+lang+".txt"))
+	}
+	if loc_proj, err := filepath.Abs(filepath.Dir(p.Infile)); err == nil && loc_proj != loc_exec {
+		scannoFiles = append(scannoFiles, filepath.Join(loc_proj, "scannos.txt"))
+	}
+	for n, sfile := range scannoFiles {
+		if _, err := os.Stat(sfile); n > 0 && os.IsNotExist(err) {
+			continue // only the data file is required
+		}
+		addScannos(readScannos(sfile))
+		if n > 0 {
+			pptr = append(pptr, fmt.Sprintf("scanno file: %s", sfile))
+		}
+	}
+
+	// load consistency groups from the optional data file, then any in
+	// the project
+	consistencyFiles := []string{filepath.Join(loc_exec, "consistency.txt")}
+	if loc_proj, err := filepath.Abs(filepath.Dir(p.Infile)); err == nil && loc_proj != loc_exec {
+		consistencyFiles = append(consistencyFiles, filepath.Join(loc_proj, "consistency.txt"))
+	}
+	for n, cfile := range consistencyFiles {
+		if _, err := os.Stat(cfile); os.IsNotExist(err) {
+			continue
+		}
+		consistencyGroups = append(consistencyGroups, readConsistency(cfile)...)
+		if n > 0 {
+			pptr = append(pptr, fmt.Sprintf("consistency file: %s", cfile))
+		}
+	}
+
+	// load any project checks
+	cfile := filepath.Join(filepath.Dir(p.Infile), "checks.txt")
+	if _, err := os.Stat(cfile); err == nil {
+		customChecks = readCustomChecks(cfile)
+		pptr = append(pptr, fmt.Sprintf("custom checks: %d from %s", len(customChecks), cfile))
+	}
+
+	// load character names
+	ufile := filepath.Join(loc_exec, "unicodedata.txt")
+	if _, err := os.Stat(ufile); err == nil {
+		readUnicodeData(ufile)
+	} else {
+		pptr = append(pptr, "no unicodedata.txt found: character names not available")
+	}
+
+	// load the Unicode confusables if present, else use the built in table
+	cfile = filepath.Join(loc_exec, "confusables.txt")
+	if _, err := os.Stat(cfile); err == nil {
+		confusables = readConfusables(cfile)
+		pptr = append(pptr, fmt.Sprintf("confusables: %d letters from %s", len(confusables), cfile))
+	}
+
+	// load the allowed character profile
+	if p.Profile != "" {
+		allowedChars = readCharProfile(p.Profile)
+		pptr = append(pptr, fmt.Sprintf("allowed characters: %s", p.Profile))
+	}
 
 	// load he/be entries
-	heMap, beMap = readHeBe(filepath.Join(loc_exec, "hebelist.txt"))
+	heMap, beMap := readHeBe(filepath.Join(loc_exec, "hebelist.txt"))
+	hebe := confusionPair{"he", "be", heMap, ""}
+	for k, v := range beMap {
+		hebe.forms[k] = v
+	}
+	confusionPairs = []confusionPair{hebe}
+
+	// load any other confusion pairs from the optional data file
+	pfile := filepath.Join(loc_exec, "pairlist.txt")
+	if _, err := os.Stat(pfile); err == nil {
+		confusionPairs = mergePairs(confusionPairs, readConfusionPairs(pfile))
+	}
 
 	// load good word list
 	howmany := 0
@@ -4307,21 +8308,40 @@ func main() {
 		pptr = append(pptr, "no good words file specified")
 	}
 
+	// normalize the working buffer to NFC so the checks see composed
+	// characters. not for the modes that write a copy of the text
+	if p.Normalize && !p.Curl && p.Fixes == "" {
+		if unicodeCCC == nil {
+			log.Fatal("normalizing (-z) needs unicodedata.txt")
+		}
+		nlines := 0
+		for i, line := range wbuf {
+			if t := nfc(line); t != line {
+				wbuf[i] = t
+				nlines++
+			}
+		}
+		pptr = append(pptr, fmt.Sprintf("normalized to NFC: %d lines changed", nlines))
+	}
+
+	// the word lists leave out the text of [Greek: ] transliterations
+	gkbuf := maskGreek(wbuf, findGreekBlocks(wbuf))
+
 	// line word list: slice of words on each line of text file (capitalization retained)
 
-	for _, line := range wbuf {
+	for _, line := range gkbuf {
 		lwl = append(lwl, getWordsOnLine(line))
 	}
 
 	// word list map to frequency of occurrence and word list map to lines where it occurs
 	// capitalization retained; apostrophes protected
 
-	wordListMapCount, wordListMapLines = getWordList(wbuf)
+	wordListMapCount, wordListMapLines = getWordList(gkbuf)
 
 	// paragraph buffer.  the user source file one paragraph per line
 
 	var cp string // current (in progress) paragraph
-	for _, element := range wbuf {
+	for n, element := range wbuf {
 		// if this is a blank line and there is a paragraph in progress, save it
 		// if not a blank line, put it into the current paragraph
 		if element == "" {
@@ -4331,6 +8351,7 @@ func main() {
 			}
 		} else {
 			if len(cp) == 0 {
+				pbufStart = append(pbufStart, n)
 				cp += element
 			} else {
 				cp = cp + " " + element
@@ -4346,10 +8367,29 @@ func main() {
 
 	// check and report punctuation style
 
+	dashStyle = getDashStyle()
+	pptr = append(pptr, fmt.Sprintf("dash convention: %s", dashStyle))
+
 	puncStyle = getPuncStyle()
 	pptr = append(pptr, fmt.Sprintf("punctuation style: %s☷", puncStyle)) // close header info
 	pptr = append(pptr, "")
 
+	// straight to curly quote conversion runs instead of the checks
+	if p.Curl {
+		for _, s := range curlQuotes() {
+			fmt.Println(s)
+		}
+		return
+	}
+
+	// auto-fix mode runs instead of the checks
+	if p.Fixes != "" {
+		for _, s := range autoFix() {
+			fmt.Println(s)
+		}
+		return
+	}
+
 	// build the links based on what was requested
 
 	s := ""
@@ -4368,6 +8408,9 @@ func main() {
 	if strings.ContainsAny(p.SelectedTests, "aj") {
 		s = s + " <a href='#jeebi'>jeebies</a> "
 	}
+	if strings.ContainsAny(p.SelectedTests, "aw") {
+		s = s + " <a href='#words'>word frequency</a> "
+	}
 
 	pptr = append(pptr, "☳reports: "+s+"☷")
 	pptr = append(pptr, "")
@@ -4438,8 +8481,23 @@ func main() {
 		pptr = append(pptr, t...)
 	}
 
-	// note: remaining words in sw are suspects.
-	// they could be used to start a user-maintained persistent good word list
+	/*************************************************************************/
+	/* word frequency                                                        */
+	/* every word with its count and a concordance of its lines              */
+	/*************************************************************************/
+
+	// run this test if "a" all or "w" word frequency
+	if strings.ContainsAny(p.SelectedTests, "aw") {
+		t := wordFreqReport()
+		pptr = append(pptr, t...)
+	}
+
+	// remaining words in sw are suspects.
+	// they can be used to start a user-maintained persistent good word list
+	if p.Candidates && strings.ContainsAny(p.SelectedTests, "ase") {
+		writeCandidates(sw)
+		pptr = append(pptr, "good word candidates saved in good_words_candidates.txt")
+	}
 
 	/*************************************************************************/
 	/* all tests complete. save results to specified report file             */